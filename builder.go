@@ -6,8 +6,8 @@ import (
 	"strings"
 
 	"github.com/meilihao/layer/clause"
+	"github.com/meilihao/layer/dialect"
 	"github.com/meilihao/layer/schema"
-	"github.com/rs/zerolog/log"
 )
 
 type SQLBuilder struct {
@@ -18,6 +18,9 @@ type SQLBuilder struct {
 	ArgColumns []*schema.Column // for select column
 	Args       []interface{}
 	dupSQL     map[*SQL]bool
+	errs       clause.Errors
+	models     map[string]*schema.Schema // models of the strict sql being built, by table name or alias
+	allModels  bool                      // all tables of the strict sql are models
 }
 
 func NewSQLBuilder(l *Layer, schema *schema.Schema, initGrow int) *SQLBuilder {
@@ -32,12 +35,13 @@ func NewSQLBuilder(l *Layer, schema *schema.Schema, initGrow int) *SQLBuilder {
 	return builder
 }
 
+// WriteQuoted write quoted and mapped identifier, dotted names like "schema.table.column" are quoted part by part.
+// only table and column names are mapped by NameMapper, schema qualifiers are written as is.
 func (b *SQLBuilder) WriteQuoted(field interface{}) {
 	switch v := field.(type) {
 	case clause.Table:
-
 		if v.Name != "" {
-			b.Builder.WriteString(b.l.dialecter.Queto(b.l.opts.nameMapper.EntityMap(v.Name)))
+			b.writeTable(v.Schema, v.Name)
 		} else {
 			b.WriteByte('(')
 			b.AppendArg(v.SubQuery)
@@ -46,76 +50,157 @@ func (b *SQLBuilder) WriteQuoted(field interface{}) {
 
 		if v.Alias != "" {
 			b.WriteString(" AS ")
-			b.Builder.WriteString(b.l.dialecter.Queto(b.l.opts.nameMapper.EntityMap(v.Alias)))
+			b.writeName(v.Alias)
 		}
 	case clause.Column:
-		if b.schema != nil {
-			c := b.schema.ColumnsByRawName[v.Name]
-			if c == nil {
-				log.Panic().Err(fmt.Errorf("%w : %v", ErrNoColumn, v)).Send()
+		if v.Table == "" {
+			if idx := strings.LastIndexByte(v.Name, '.'); idx > 0 {
+				v.Table, v.Name = v.Name[:idx], v.Name[idx+1:]
 			}
+		}
 
-			b.Columns = append(b.Columns, c)
+		if b.schema != nil {
+			if c := b.schema.ColumnsByRawName[v.Name]; c != nil {
+				b.Columns = append(b.Columns, c)
+			} else if v.Name != "*" {
+				b.AddError(&clause.BuildError{Column: v.Name, Err: fmt.Errorf("%w in %s", ErrNoColumn, b.schema.Name)})
+				// placeholder, so the next arg is not bound to the previous column
+				b.Columns = append(b.Columns, nil)
+			}
+		} else if b.models != nil {
+			b.checkModelColumn(v)
 		}
 
 		if v.Table != "" {
-			b.Builder.WriteString(b.l.dialecter.Queto(b.l.opts.nameMapper.EntityMap(v.Table)))
+			b.writeTable("", v.Table)
 			b.WriteByte('.')
 		}
 
 		if v.Name == "*" {
 			b.Builder.WriteByte('*')
 		} else {
-			b.Builder.WriteString(b.l.dialecter.Queto(b.l.opts.nameMapper.EntityMap(v.Name)))
+			b.writeName(v.Name)
 		}
 
 		if v.Alias != "" {
 			b.WriteString(" AS ")
-			b.Builder.WriteString(b.l.dialecter.Queto(b.l.opts.nameMapper.EntityMap(v.Alias)))
+			b.writeName(v.Alias)
 		}
 	default:
-		b.Builder.WriteString(b.l.dialecter.Queto(b.l.opts.nameMapper.EntityMap(fmt.Sprint(field))))
+		b.writeTable("", fmt.Sprint(field))
 	}
 }
 
-func (b *SQLBuilder) AppendArg(args ...interface{}) {
-	if b.schema != nil {
-		b.ArgColumns = append(b.ArgColumns, b.Columns[len(b.Columns)-1])
+// writeTable write [schema.]name, the part before the last dot of name is schema if schema is empty
+func (b *SQLBuilder) writeTable(schema, name string) {
+	if schema == "" {
+		if idx := strings.LastIndexByte(name, '.'); idx > 0 {
+			schema, name = name[:idx], name[idx+1:]
+		}
+	}
+
+	if schema != "" {
+		for _, v := range strings.Split(schema, ".") {
+			b.Builder.WriteString(b.l.dialecter.Queto(v))
+			b.WriteByte('.')
+		}
+	}
+
+	b.writeName(name)
+}
+
+func (b *SQLBuilder) writeName(name string) {
+	b.Builder.WriteString(b.l.dialecter.Queto(b.l.opts.nameMapper.EntityMap(name)))
+}
+
+// Dialect dialect of l
+func (b *SQLBuilder) Dialect() dialect.Dialecter {
+	return b.l.dialecter
+}
+
+// AddError record an invalid column or clause
+func (b *SQLBuilder) AddError(err error) {
+	if err != nil {
+		b.errs = append(b.errs, err)
+	}
+}
+
+// Err returns errors recorded by AddError
+func (b *SQLBuilder) Err() error {
+	switch len(b.errs) {
+	case 0:
+		return nil
+	case 1:
+		return b.errs[0]
 	}
 
+	return b.errs
+}
+
+func (b *SQLBuilder) AppendArg(args ...interface{}) {
 	for idx, v := range args {
 		if idx > 0 {
 			b.WriteByte(',')
 		}
 
-		switch v := v.(type) {
-		case driver.Valuer:
-			b.Args = append(b.Args, v)
-			b.Builder.WriteString(b.l.dialecter.Arg(len(b.Args)))
-		case clause.Expr:
-			var varStr strings.Builder
-			var sql = v.Sql
-
-			for _, arg := range v.Args {
-				b.Args = append(b.Args, arg)
-				varStr.WriteString(b.l.dialecter.Arg(len(b.Args)))
-				sql = strings.Replace(sql, "?", varStr.String(), 1)
-			}
+		b.appendArg(v)
+	}
+}
 
-			b.WriteString(sql)
-		case *SQL:
-			sql, args, err := v.Build(b.l, b.schema, 128)
-			if err != nil {
-				log.Error().Err(err).Send()
+// modelTable table of model, table overrides the model's table name, it carries its own schema if it is dotted
+func modelTable(sc *schema.Schema, table string) clause.Table {
+	if strings.IndexByte(table, '.') > 0 {
+		return clause.Table{Name: table}
+	}
+
+	return clause.Table{Schema: sc.TableSchema, Name: table}
+}
 
-				return
+func (b *SQLBuilder) appendArg(v interface{}) {
+	switch v := v.(type) {
+	case driver.Valuer:
+		b.bindArg(v)
+	case clause.Expr:
+		idx := 0
+		for i := 0; i < len(v.Sql); i++ {
+			if v.Sql[i] == '?' && idx < len(v.Args) {
+				b.appendArg(v.Args[idx])
+				idx++
+			} else {
+				b.WriteByte(v.Sql[i])
 			}
+		}
+	case *SQL:
+		// build sub query into b, so its args are in order and placeholders are numbered after the outer ones.
+		// columns of the sub query are of its own tables, not the schema of b
+		n := len(b.errs)
+		sc := b.schema
+		b.schema = nil
+		if err := v.build(b); err != nil && len(b.errs) == n {
+			b.AddError(err)
+		}
+		b.schema = sc
+	case clause.Expression:
+		if err := v.Build(b); err != nil {
+			b.AddError(err)
+		}
+	default:
+		b.bindArg(v)
+	}
+}
 
-			b.WriteString(sql)
-			b.Args = append(b.Args, args...)
-		default:
-			b.Args = append(b.Args, v)
-			b.Builder.WriteString(b.l.dialecter.Arg(len(b.Args)))
+// bindArg add v to Args and write its placeholder
+func (b *SQLBuilder) bindArg(v interface{}) {
+	if b.schema != nil {
+		if len(b.Columns) == 0 {
+			b.AddError(&clause.BuildError{Err: ErrArgWithoutColumn})
+
+			return
 		}
+
+		b.ArgColumns = append(b.ArgColumns, b.Columns[len(b.Columns)-1])
 	}
+
+	b.Builder.WriteString(b.l.dialecter.Arg(len(b.Args)))
+	b.Args = append(b.Args, v)
 }
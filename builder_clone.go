@@ -0,0 +1,170 @@
+package layer
+
+import (
+	"github.com/meilihao/layer/clause"
+)
+
+// Freeze make s immutable, then every method which changes s works on a copy of it and returns the copy.
+// so a frozen base query can be shared, like a package-level value, and derived concurrently:
+//
+//	var base = layer.Select("Id").From("user").Where(clause.Eq("Status", 1)).Freeze()
+//	q := base.Where(clause.Gt("Id", 10)).Bind(l) // base is not changed
+func (s *SQL) Freeze() *SQL {
+	s.frozen = true
+
+	return s
+}
+
+// IsFrozen whether s is frozen by Freeze()
+func (s *SQL) IsFrozen() bool {
+	return s.frozen
+}
+
+// cow copy on write, returns a mutable clone if s is frozen
+func (s *SQL) cow() *SQL {
+	if !s.frozen {
+		return s
+	}
+
+	return s.Clone()
+}
+
+// Clone deep copy s, the clone is not frozen. union members and sub queries of FROM, JOIN and WITH are cloned
+// unless they are frozen, sub queries used as args of expressions are shared.
+func (s *SQL) Clone() *SQL {
+	return s.clone(make(map[*SQL]*SQL))
+}
+
+func (s *SQL) clone(done map[*SQL]*SQL) *SQL {
+	if c, ok := done[s]; ok {
+		return c
+	}
+
+	c := &SQL{
+		err:        s.err,
+		typ:        s.typ,
+		l:          s.l,
+		model:      s.model,
+		models:     append([]sqlModel(nil), s.models...),
+		strict:     s.strict,
+		softDelete: s.softDelete,
+	}
+	done[s] = c
+
+	c.Clauses = cloneClauses(s.Clauses, done)
+	if s.compoundClauses != nil {
+		c.compoundClauses = cloneClauses(s.compoundClauses, done)
+	}
+	if len(s.compounds) > 0 {
+		c.compounds = make([]compound, len(s.compounds))
+		for i, v := range s.compounds {
+			c.compounds[i] = compound{typ: v.typ, sql: cloneSub(v.sql, done)}
+		}
+	}
+
+	return c
+}
+
+// cloneSub clone sub query unless it is frozen
+func cloneSub(s *SQL, done map[*SQL]*SQL) *SQL {
+	if s == nil || s.frozen {
+		return s
+	}
+
+	return s.clone(done)
+}
+
+func cloneClauses(cs clause.Clauses, done map[*SQL]*SQL) clause.Clauses {
+	if cs == nil {
+		return make(clause.Clauses, 3)
+	}
+
+	m := make(clause.Clauses, len(cs))
+	for k, v := range cs {
+		m[k] = cloneClause(v, done)
+	}
+
+	return m
+}
+
+// cloneClause copy the pointers and slices which are changed by the methods of SQL
+func cloneClause(e clause.Expression, done map[*SQL]*SQL) clause.Expression {
+	switch v := e.(type) {
+	case *clause.Select:
+		t := *v
+		t.Columns = append([]interface{}(nil), v.Columns...)
+		return &t
+	case *clause.Update:
+		t := *v
+		return &t
+	case *clause.Delete:
+		t := *v
+		t.Targets = append([]clause.Table(nil), v.Targets...)
+		return &t
+	case *clause.Insert:
+		t := *v
+		return &t
+	case *clause.InsertSelect:
+		t := *v
+		t.Columns = append([]clause.Column(nil), v.Columns...)
+		return &t
+	case *clause.Set:
+		t := append(clause.Set(nil), *v...)
+		return &t
+	case *clause.Values:
+		t := append(clause.Values(nil), *v...)
+		return &t
+	case *clause.From:
+		t := *v
+		t.Tables = make([]clause.Table, len(v.Tables))
+		for i, table := range v.Tables {
+			t.Tables[i] = cloneTable(table, done)
+		}
+		t.Joins = make([]clause.Join, len(v.Joins))
+		for i, j := range v.Joins {
+			j.Table = cloneTable(j.Table, done)
+			t.Joins[i] = j
+		}
+		return &t
+	case *clause.With:
+		t := *v
+		t.CTEs = append([]clause.CTE(nil), v.CTEs...)
+		for i, cte := range t.CTEs {
+			if sub, ok := cte.Query.(*SQL); ok {
+				t.CTEs[i].Query = cloneSub(sub, done)
+			}
+		}
+		return &t
+	case *clause.Where:
+		t := *v
+		t.Exprs = append([]clause.Expression(nil), v.Exprs...)
+		return &t
+	case *clause.OrderBy:
+		t := *v
+		t.Columns = append([]interface{}(nil), v.Columns...)
+		return &t
+	case *clause.Limit:
+		t := *v
+		return &t
+	case *clause.GroupBy:
+		t := *v
+		t.Columns = append([]clause.Column(nil), v.Columns...)
+		t.Having = append([]clause.Expression(nil), v.Having...)
+		return &t
+	case *clause.Windows:
+		t := *v
+		t.Windows = append([]clause.NamedWindow(nil), v.Windows...)
+		return &t
+	}
+
+	// values, like clause.Locking, are replaced but not changed
+	return e
+}
+
+func cloneTable(t clause.Table, done map[*SQL]*SQL) clause.Table {
+	if sub, ok := t.SubQuery.(*SQL); ok {
+		t.SubQuery = cloneSub(sub, done)
+	}
+
+	return t
+}
@@ -0,0 +1,80 @@
+package layer
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/meilihao/layer/clause"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuilder_Clone(t *testing.T) {
+	sub := Select("Id").From("t2")
+	base := Select("Id").From("t1").Join(clause.InnerJoin("t3").On(clause.Expr{Sql: "t1.id = t3.id"})).
+		Where(clause.Eq("A", 1)).Union(sub).OrderBy("Id").Limit(10)
+
+	c := base.Clone()
+	c.Join(clause.LeftJoin("t4").On(clause.Expr{Sql: "t1.id = t4.id"})).Where(clause.Eq("B", 2)).OrderBy("-A").Limit(5, 5)
+	c.compounds[0].sql.Where(clause.Eq("C", 3))
+
+	sql, args, err := base.Build(l, nil, 0)
+	assert.NoError(t, err)
+	assert.EqualValues(t, "(SELECT `id` FROM `t1` INNER JOIN `t3` ON t1.id = t3.id WHERE `a` = ?) UNION (SELECT `id` FROM `t2`) ORDER BY `id` ASC LIMIT 10", sql)
+	assert.EqualValues(t, []interface{}{1}, args)
+
+	sql, args, err = c.Build(l, nil, 0)
+	assert.NoError(t, err)
+	assert.EqualValues(t, "(SELECT `id` FROM `t1` INNER JOIN `t3` ON t1.id = t3.id LEFT JOIN `t4` ON t1.id = t4.id WHERE `a` = ? AND `b` = ?) UNION (SELECT `id` FROM `t2` WHERE `c` = ?) ORDER BY `id` ASC,`a` DESC LIMIT 5 OFFSET 5", sql)
+	assert.EqualValues(t, []interface{}{1, 2, 3}, args)
+
+	// sub queries of FROM and WITH are cloned too
+	base = Select("Id").From(Select("Id").From("t1"), "x").With("w", Select("Id").From("t2"))
+	c = base.Clone()
+	c.Clauses[clause.ClauseFrom].(*clause.From).Tables[0].SubQuery.(*SQL).Where(clause.Eq("A", 1))
+	c.Clauses[clause.ClauseWith].(*clause.With).CTEs[0].Query.(*SQL).Where(clause.Eq("B", 2))
+	sql, _, err = base.Build(l, nil, 0)
+	assert.NoError(t, err)
+	assert.EqualValues(t, "WITH `w` AS (SELECT `id` FROM `t2`) SELECT `id` FROM (SELECT `id` FROM `t1`) AS `x`", sql)
+}
+
+func TestBuilder_Freeze(t *testing.T) {
+	base := Select("Id").From("user").Where(clause.Eq("Status", 1)).Freeze()
+	assert.True(t, base.IsFrozen())
+
+	q1 := base.Where(clause.Gt("Id", 10)).OrderBy("-Id")
+	q2 := base.Where(clause.Lt("Id", 5)).Limit(1)
+	assert.False(t, q1.IsFrozen())
+	assert.True(t, q1 != base)
+
+	sql, _, err := base.Build(l, nil, 0)
+	assert.NoError(t, err)
+	assert.EqualValues(t, "SELECT `id` FROM `user` WHERE `status` = ?", sql)
+
+	sql, args, err := q1.Build(l, nil, 0)
+	assert.NoError(t, err)
+	assert.EqualValues(t, "SELECT `id` FROM `user` WHERE `status` = ? AND `id` > ? ORDER BY `id` DESC", sql)
+	assert.EqualValues(t, []interface{}{1, 10}, args)
+
+	sql, _, err = q2.Build(l, nil, 0)
+	assert.NoError(t, err)
+	assert.EqualValues(t, "SELECT `id` FROM `user` WHERE `status` = ? AND `id` < ? LIMIT 1", sql)
+
+	// Bind works on a copy too
+	assert.Nil(t, base.l)
+	assert.True(t, base.Bind(l).l == l)
+	assert.Nil(t, base.l)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			sql, args, err := base.Where(clause.Eq("Id", i)).Build(l, nil, 0)
+			assert.NoError(t, err)
+			assert.EqualValues(t, "SELECT `id` FROM `user` WHERE `status` = ? AND `id` = ?", sql)
+			assert.EqualValues(t, []interface{}{1, i}, args)
+		}(i)
+	}
+	wg.Wait()
+}
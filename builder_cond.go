@@ -1,7 +1,12 @@
 package layer
 
 import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"reflect"
 	"sort"
+	"strings"
 
 	"github.com/meilihao/layer/clause"
 )
@@ -14,6 +19,15 @@ type Cond interface {
 	//IsValid() bool
 }
 
+var (
+	ErrInvalidCondValue = errors.New("layer : invalid condition value")
+)
+
+// condOps operators allowed as key suffix of Eq, longer ones first
+var condOps = []string{"NOT LIKE", "NOT IN", "LIKE", "IN", ">=", "<=", "!=", "<>", ">", "<", "="}
+
+// Eq map of column and value, joined by AND.
+// slice value is expanded to IN, nil value to IS NULL, and key can end with an operator, like "age >", "name LIKE" or "id !=".
 type Eq map[string]interface{}
 
 // And implements And with other conditions
@@ -27,32 +41,246 @@ func (eq Eq) Or(conds ...Cond) Cond {
 }
 
 func (eq Eq) Build(builder clause.Builder) error {
-	n := len(eq)
-	for idx, k := range eq.sortedKeys() {
-		v := eq[k]
-		// switch v.(type) {
-		// case int:
-		if err := (clause.Eq(k, v)).Build(builder); err != nil {
-			return err
+	return buildCondMap(builder, eq, splitCondKey)
+}
+
+// Neq map of column and value, nil value is IS NOT NULL and slice value is NOT IN
+type Neq map[string]interface{}
+
+func (neq Neq) And(conds ...Cond) Cond {
+	return And(neq, And(conds...))
+}
+
+func (neq Neq) Or(conds ...Cond) Cond {
+	return Or(neq, Or(conds...))
+}
+
+func (neq Neq) Build(builder clause.Builder) error {
+	return buildCondMap(builder, neq, fixedCondOp("<>"))
+}
+
+// Gt map of column and value for >
+type Gt map[string]interface{}
+
+func (gt Gt) And(conds ...Cond) Cond {
+	return And(gt, And(conds...))
+}
+
+func (gt Gt) Or(conds ...Cond) Cond {
+	return Or(gt, Or(conds...))
+}
+
+func (gt Gt) Build(builder clause.Builder) error {
+	return buildCondMap(builder, gt, fixedCondOp(">"))
+}
+
+// Lt map of column and value for <
+type Lt map[string]interface{}
+
+func (lt Lt) And(conds ...Cond) Cond {
+	return And(lt, And(conds...))
+}
+
+func (lt Lt) Or(conds ...Cond) Cond {
+	return Or(lt, Or(conds...))
+}
+
+func (lt Lt) Build(builder clause.Builder) error {
+	return buildCondMap(builder, lt, fixedCondOp("<"))
+}
+
+// Like map of column and pattern for LIKE
+type Like map[string]interface{}
+
+func (like Like) And(conds ...Cond) Cond {
+	return And(like, And(conds...))
+}
+
+func (like Like) Or(conds ...Cond) Cond {
+	return Or(like, Or(conds...))
+}
+
+func (like Like) Build(builder clause.Builder) error {
+	return buildCondMap(builder, like, fixedCondOp("LIKE"))
+}
+
+// Between map of column and range, value is a slice or an array with 2 elements
+type Between map[string]interface{}
+
+func (between Between) And(conds ...Cond) Cond {
+	return And(between, And(conds...))
+}
+
+func (between Between) Or(conds ...Cond) Cond {
+	return Or(between, Or(conds...))
+}
+
+func (between Between) Build(builder clause.Builder) error {
+	return buildCondMap(builder, between, fixedCondOp("BETWEEN"))
+}
+
+func fixedCondOp(op string) func(string) (string, string) {
+	return func(k string) (string, string) {
+		return k, op
+	}
+}
+
+// splitCondKey split key into column and operator, operator is "=" if key has no one
+func splitCondKey(key string) (string, string) {
+	key = strings.TrimSpace(key)
+	upper := strings.ToUpper(key)
+
+	for _, op := range condOps {
+		if !strings.HasSuffix(upper, op) {
+			continue
 		}
 
-		//}
+		col := key[:len(key)-len(op)]
+		// word operator must be separated from column
+		if op[0] >= 'A' && op[0] <= 'Z' && !strings.HasSuffix(col, " ") {
+			continue
+		}
+		if col = strings.TrimSpace(col); col == "" {
+			continue
+		}
 
-		if idx != n-1 {
-			builder.WriteString(" AND ")
+		if op == "!=" {
+			op = "<>"
 		}
+		return col, op
 	}
 
-	return nil
+	return key, "="
 }
 
-func (eq Eq) sortedKeys() []string {
-	keys := make([]string, 0, len(eq))
-	for key := range eq {
+func buildCondMap(builder clause.Builder, m map[string]interface{}, split func(string) (string, string)) error {
+	keys := make([]string, 0, len(m))
+	for key := range m {
 		keys = append(keys, key)
 	}
 	sort.Strings(keys)
-	return keys
+
+	for idx, k := range keys {
+		if idx > 0 {
+			builder.WriteString(" AND ")
+		}
+
+		col, op := split(k)
+		if err := buildCondItem(builder, col, op, m[k]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func buildCondItem(builder clause.Builder, col, op string, v interface{}) error {
+	values, isList := condList(v)
+
+	switch op {
+	case "=", "<>":
+		if v == nil {
+			if op == "=" {
+				return clause.IsNULL(col).Build(builder)
+			}
+			return clause.NotNULL(col).Build(builder)
+		}
+		if isList {
+			return buildCondIn(builder, col, op == "<>", values)
+		}
+		if op == "=" {
+			return clause.Eq(col, v).Build(builder)
+		}
+		return clause.Neq(col, v).Build(builder)
+	case "IN", "NOT IN":
+		if !isList {
+			values = []interface{}{v}
+		}
+		return buildCondIn(builder, col, op == "NOT IN", values)
+	case "BETWEEN":
+		if !isList || len(values) != 2 {
+			return fmt.Errorf("%w: BETWEEN %s needs 2 values", ErrInvalidCondValue, col)
+		}
+		return clause.Between(col, values[0], values[1]).Build(builder)
+	}
+
+	if v == nil || isList {
+		return fmt.Errorf("%w: %s %s needs a single value", ErrInvalidCondValue, col, op)
+	}
+
+	switch op {
+	case ">":
+		return clause.Gt(col, v).Build(builder)
+	case ">=":
+		return clause.Gte(col, v).Build(builder)
+	case "<":
+		return clause.Lt(col, v).Build(builder)
+	case "<=":
+		return clause.Lte(col, v).Build(builder)
+	case "LIKE":
+		return clause.Like(col, v).Build(builder)
+	default:
+		return clause.NotLike(col, v).Build(builder)
+	}
+}
+
+// buildCondIn empty IN is always false and empty NOT IN is always true
+func buildCondIn(builder clause.Builder, col string, isNot bool, values []interface{}) error {
+	if len(values) == 0 {
+		if isNot {
+			builder.WriteString("1 = 1")
+		} else {
+			builder.WriteString("1 = 0")
+		}
+		return nil
+	}
+
+	builder.WriteQuoted(condColumn(col))
+	if isNot {
+		builder.WriteString(" NOT IN (")
+	} else {
+		builder.WriteString(" IN (")
+	}
+	builder.AppendArg(values...)
+	builder.WriteByte(')')
+
+	return nil
+}
+
+func condColumn(col string) clause.Column {
+	if idx := strings.LastIndexByte(col, '.'); idx > 0 {
+		return clause.Column{Table: col[:idx], Name: col[idx+1:]}
+	}
+
+	return clause.Column{Name: col}
+}
+
+// condList expand slice and array except []byte and driver.Valuer
+func condList(v interface{}) ([]interface{}, bool) {
+	if v == nil {
+		return nil, false
+	}
+	if _, ok := v.(driver.Valuer); ok {
+		return nil, false
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Slice:
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			return nil, false
+		}
+	case reflect.Array:
+	default:
+		return nil, false
+	}
+
+	values := make([]interface{}, rv.Len())
+	for i := range values {
+		values[i] = rv.Index(i).Interface()
+	}
+
+	return values, true
 }
 
 type condAnd []Cond
@@ -146,11 +374,21 @@ func (or condOr) Build(builder clause.Builder) error {
 	var err error
 	for i, cond := range or {
 		var needQuote bool
-		switch cond.(type) {
-		case condAnd, expr:
+		switch v := cond.(type) {
+		case condAnd, expr, condExample:
 			needQuote = true
 		case Eq:
-			needQuote = (len(cond.(Eq)) > 1)
+			needQuote = len(v) > 1
+		case Neq:
+			needQuote = len(v) > 1
+		case Gt:
+			needQuote = len(v) > 1
+		case Lt:
+			needQuote = len(v) > 1
+		case Like:
+			needQuote = len(v) > 1
+		case Between:
+			needQuote = len(v) > 1
 		}
 
 		if needQuote {
@@ -0,0 +1,69 @@
+package layer
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuilder_CondMap(t *testing.T) {
+	type result struct {
+		Sql  string
+		Args []interface{}
+	}
+
+	results := []struct {
+		Cond  Cond
+		Mysql result
+		Pg    result
+	}{
+		{
+			Eq{"id": []int{1, 2}, "deleted_at": nil, "name": "a"},
+			result{"`deleted_at` IS NULL AND `id` IN (?,?) AND `name` = ?", []interface{}{1, 2, "a"}},
+			result{`"deleted_at" IS NULL AND "id" IN ($1,$2) AND "name" = $3`, []interface{}{1, 2, "a"}},
+		},
+		{
+			Eq{"id": []int{}},
+			result{"1 = 0", nil},
+			result{"1 = 0", nil},
+		},
+		{
+			Eq{"age >": 18, "name LIKE": "a%", "id !=": 3, "t.score<=": 60, "kind not in": [2]string{"a", "b"}},
+			result{"`age` > ? AND `id` <> ? AND `kind` NOT IN (?,?) AND `name` LIKE ? AND `t`.`score` <= ?", []interface{}{18, 3, "a", "b", "a%", 60}},
+			result{`"age" > $1 AND "id" <> $2 AND "kind" NOT IN ($3,$4) AND "name" LIKE $5 AND "t"."score" <= $6`, []interface{}{18, 3, "a", "b", "a%", 60}},
+		},
+		{
+			Eq{"id IN": 1, "data": []byte("x")},
+			result{"`data` = ? AND `id` IN (?)", []interface{}{[]byte("x"), 1}},
+			result{`"data" = $1 AND "id" IN ($2)`, []interface{}{[]byte("x"), 1}},
+		},
+		{
+			Neq{"a": nil, "b": []int{}, "c": []int{1}, "d": 2},
+			result{"`a` IS NOT NULL AND 1 = 1 AND `c` NOT IN (?) AND `d` <> ?", []interface{}{1, 2}},
+			result{`"a" IS NOT NULL AND 1 = 1 AND "c" NOT IN ($1) AND "d" <> $2`, []interface{}{1, 2}},
+		},
+		{
+			Or(Gt{"a": 1}, Lt{"b": 2, "c": 3}, Like{"d": "x%"}, Between{"e": []int{1, 9}}),
+			result{"`a` > ? OR (`b` < ? AND `c` < ?) OR `d` LIKE ? OR `e` BETWEEN ? AND ?", []interface{}{1, 2, 3, "x%", 1, 9}},
+			result{`"a" > $1 OR ("b" < $2 AND "c" < $3) OR "d" LIKE $4 OR "e" BETWEEN $5 AND $6`, []interface{}{1, 2, 3, "x%", 1, 9}},
+		},
+	}
+
+	for _, v := range results {
+		b := NewSQLBuilder(l, nil, 0)
+		assert.NoError(t, v.Cond.Build(b))
+		assert.EqualValues(t, v.Mysql.Sql, b.String())
+		assert.EqualValues(t, v.Mysql.Args, b.Args)
+
+		b = NewSQLBuilder(pg, nil, 0)
+		assert.NoError(t, v.Cond.Build(b))
+		assert.EqualValues(t, v.Pg.Sql, b.String())
+		assert.EqualValues(t, v.Pg.Args, b.Args)
+	}
+
+	for _, c := range []Cond{Eq{"age >": nil}, Gt{"a": []int{1}}, Between{"a": 1}, Between{"a": []int{1, 2, 3}}} {
+		b := NewSQLBuilder(l, nil, 0)
+		assert.True(t, errors.Is(c.Build(b), ErrInvalidCondValue))
+	}
+}
@@ -0,0 +1,60 @@
+package layer
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/meilihao/layer/clause"
+	"github.com/meilihao/layer/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+type errUser struct {
+	Id   int `layer:";pk"`
+	Name string
+}
+
+func TestBuilder_Error(t *testing.T) {
+	s, err := schema.Parse(&errUser{}, l.opts.nameMapper)
+	assert.NoError(t, err)
+
+	b := Select("Id", "Nmae").From("err_user").Where(clause.Eq("Age", 1))
+	_, _, err = b.Build(l, s, 0)
+	assert.Error(t, err)
+	assert.True(t, errors.Is(err, ErrNoColumn))
+
+	var be *clause.BuildError
+	assert.True(t, errors.As(err, &be))
+	assert.EqualValues(t, clause.ClauseSelect, be.Clause)
+	assert.EqualValues(t, "Nmae", be.Column)
+	assert.EqualValues(t, "layer : build clause SELECT column Nmae: no column in errUser", be.Error())
+
+	// all invalid columns of a clause are reported
+	b = Select("Id").From("err_user").Where(clause.Eq("Age", 1), clause.Eq("Sex", 1))
+	_, _, err = b.Build(l, s, 0)
+	var es clause.Errors
+	assert.True(t, errors.As(err, &es))
+	assert.EqualValues(t, 2, len(es))
+	assert.EqualValues(t, "layer : build clause WHERE column Age: no column in errUser; layer : build clause WHERE column Sex: no column in errUser", err.Error())
+
+	// the arg of an unknown column is not bound to the previous column
+	sb := NewSQLBuilder(l, s, 0)
+	assert.NoError(t, clause.Eq("Id", 1).Build(sb))
+	assert.NoError(t, clause.Eq("Age", 2).Build(sb))
+	assert.EqualValues(t, []*schema.Column{s.ColumnsByRawName["Id"], nil}, sb.ArgColumns)
+
+	b = Select("Id").From(1)
+	_, _, err = b.Build(l, nil, 0)
+	assert.True(t, errors.Is(err, ErrNoSupportedInput))
+
+	b = Select("Id").From("t1").Join(1)
+	_, _, err = b.Build(l, nil, 0)
+	assert.True(t, errors.Is(err, ErrNoSupportedInput))
+
+	b = Select("Id", 1).From("t1")
+	_, _, err = b.Build(l, nil, 0)
+	assert.True(t, errors.Is(err, clause.ErrUnsupportedColumn))
+
+	_, err = New(WithDB("oracle", "xxx"), WithRunTest(true))
+	assert.True(t, errors.Is(err, ErrUnsupportedDriverName))
+}
@@ -0,0 +1,156 @@
+package layer
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/meilihao/layer/clause"
+	"github.com/meilihao/layer/utils"
+)
+
+var ErrEmptyExample = errors.New("layer : no field in example")
+
+type condExample struct {
+	model      interface{}
+	include    map[string]bool
+	like       bool
+	allowEmpty bool
+}
+
+var _ Cond = condExample{}
+
+// ExampleOption option of Example
+type ExampleOption func(*condExample)
+
+// ExampleInclude use the fields even if they are zero, fields are raw column names, like "UserName"
+func ExampleInclude(fields ...string) ExampleOption {
+	return func(e *condExample) {
+		for _, f := range fields {
+			e.include[f] = true
+		}
+	}
+}
+
+// ExampleLike use LIKE for string fields, the value is used as pattern as is
+func ExampleLike() ExampleOption {
+	return func(e *condExample) {
+		e.like = true
+	}
+}
+
+// ExampleAllowEmpty an example without fields matches everything, otherwise it is ErrEmptyExample
+func ExampleAllowEmpty() ExampleOption {
+	return func(e *condExample) {
+		e.allowEmpty = true
+	}
+}
+
+// Example query by example, generates AND conditions from the non-zero fields of model.
+// fields tagged `layer:"-"` are not columns so are ignored, JSON columns use JSON containment and XML columns are skipped.
+func Example(model interface{}, opts ...ExampleOption) Cond {
+	e := condExample{
+		model:   model,
+		include: make(map[string]bool),
+	}
+
+	for _, opt := range opts {
+		opt(&e)
+	}
+
+	return e
+}
+
+func (e condExample) And(conds ...Cond) Cond {
+	return And(e, And(conds...))
+}
+
+func (e condExample) Or(conds ...Cond) Cond {
+	return Or(e, Or(conds...))
+}
+
+func (e condExample) Build(builder clause.Builder) error {
+	if e.model == nil {
+		return ErrNil
+	}
+
+	rv := reflect.ValueOf(e.model)
+	if rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return ErrUsingNilPtrModelData
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return ErrUsingNotStructModel
+	}
+	// a copy which is not addressable, so nil embedded pointers are not allocated by FieldValue
+	rv = reflect.ValueOf(rv.Interface())
+
+	sc, err := parseModel(builder, e.model)
+	if err != nil {
+		return err
+	}
+
+	for name := range e.include {
+		if _, ok := sc.ColumnsByRawName[name]; !ok {
+			return fmt.Errorf("%w: %s in %s", ErrNoColumn, name, sc.Name)
+		}
+	}
+
+	n := 0
+	for _, c := range sc.Columns {
+		f := c.Field
+		if f.IsXML {
+			continue
+		}
+
+		fv, ok := c.FieldValue(rv)
+		isZero := !ok || utils.IsZero(fv) || (fv.Kind() == reflect.Struct && fv.IsZero())
+		if isZero && !e.include[c.RawName] {
+			continue
+		}
+
+		if n > 0 {
+			builder.WriteString(" AND ")
+		}
+		n++
+
+		if !ok || (isZero && f.IsPointer) {
+			if err = clause.IsNULL(c.RawName).Build(builder); err != nil {
+				return err
+			}
+			continue
+		}
+
+		var item clause.Expression
+		if f.IsJSON && !f.IsValuer {
+			item = clause.JSONContains(c.RawName, fv.Interface())
+		} else {
+			v, err := c.Get(rv)
+			if err != nil {
+				return err
+			}
+
+			if e.like && f.IndirectFieldType.Kind() == reflect.String {
+				item = clause.Like(c.RawName, v)
+			} else {
+				item = clause.Eq(c.RawName, v)
+			}
+		}
+
+		if err = item.Build(builder); err != nil {
+			return err
+		}
+	}
+
+	// no field matches everything, which must be asked for, so that update and delete are not run on all rows by mistake
+	if n == 0 {
+		if !e.allowEmpty {
+			return fmt.Errorf("%w: %s", ErrEmptyExample, sc.Name)
+		}
+		builder.WriteString("1 = 1")
+	}
+
+	return nil
+}
@@ -0,0 +1,65 @@
+package layer
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/meilihao/layer/clause"
+	"github.com/stretchr/testify/assert"
+)
+
+type exampleUser struct {
+	Id    int `layer:";pk"`
+	Name  string
+	Age   *int
+	Score int
+	Tmp   string                 `layer:"-"`
+	Attrs map[string]interface{} `layer:";json"`
+}
+
+func TestBuilder_Example(t *testing.T) {
+	age := 18
+
+	b := Select("Id").From(&exampleUser{}).Where(Example(&exampleUser{Name: "a", Age: &age, Tmp: "x"}))
+	sql, args, err := b.Build(l, nil, 0)
+	assert.NoError(t, err)
+	assert.EqualValues(t, "SELECT `id` FROM `example_user` WHERE `name` = ? AND `age` = ?", sql)
+	assert.EqualValues(t, []interface{}{"a", &age}, args)
+
+	// included zero fields, nil pointer is IS NULL
+	b = Select("Id").From(&exampleUser{}).Where(Example(exampleUser{Name: "a%"}, ExampleLike(), ExampleInclude("Score", "Age")), clause.Gt("Id", 1))
+	sql, args, err = b.Build(l, nil, 0)
+	assert.NoError(t, err)
+	assert.EqualValues(t, "SELECT `id` FROM `example_user` WHERE `name` LIKE ? AND `age` IS NULL AND `score` = ? AND `id` > ?", sql)
+	assert.EqualValues(t, []interface{}{"a%", 0, 1}, args)
+
+	b = Select("Id").From(&exampleUser{}).Where(Example(&exampleUser{Attrs: map[string]interface{}{"a": 1}}).Or(Eq{"Id": 2}))
+	sql, args, err = b.Build(pg, nil, 0)
+	assert.NoError(t, err)
+	assert.EqualValues(t, `SELECT "id" FROM "example_user" WHERE (("attrs" @> CAST($1 AS JSONB))) OR "id" = $2`, sql)
+	assert.EqualValues(t, []interface{}{`{"a":1}`, 2}, args)
+
+	b = Select("Id").From(&exampleUser{}).Where(Example(&exampleUser{}, ExampleAllowEmpty()))
+	sql, _, err = b.Build(l, nil, 0)
+	assert.NoError(t, err)
+	assert.EqualValues(t, "SELECT `id` FROM `example_user` WHERE 1 = 1", sql)
+
+	_, _, err = Select("Id").From(&exampleUser{}).Where(Example(&exampleUser{})).Build(l, nil, 0)
+	assert.True(t, errors.Is(err, ErrEmptyExample))
+
+	// an empty example does not delete all rows
+	_, err = l.NewDeleteSession().DryRun().NoPK().Where(Example(&exampleUser{})).Delete(&exampleUser{Id: 3})
+	assert.True(t, errors.Is(err, ErrEmptyExample))
+
+	se := l.NewFindSession().DryRun().NoPK().Where(Example(&exampleUser{Id: 3}))
+	var us []*exampleUser
+	_, err = se.Find(&us)
+	assert.NoError(t, err)
+	assert.EqualValues(t, "SELECT `id`,`name`,`age`,`score`,`attrs` FROM `example_user` WHERE `id` = ?", se.builder.String())
+
+	_, _, err = Select("Id").From("t").Where(Example(&exampleUser{}, ExampleInclude("Nmae"))).Build(l, nil, 0)
+	assert.True(t, errors.Is(err, ErrNoColumn))
+
+	_, _, err = Select("Id").From("t").Where(Example(1)).Build(l, nil, 0)
+	assert.True(t, errors.Is(err, ErrUsingNotStructModel))
+}
@@ -0,0 +1,154 @@
+package layer
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/meilihao/layer/clause"
+	"github.com/meilihao/layer/schema"
+)
+
+var (
+	ErrUnboundSQL = errors.New("layer : sql is not bound to a Layer, call Bind() first")
+)
+
+// Bind bind s to l for Exec, Rows, All, One and Scalar, use l.WithTx(tx) to run s in a transaction.
+// union members and sub queries inherit it from the main one.
+func (s *SQL) Bind(l *Layer) *SQL {
+	s = s.cow()
+	s.l = l
+
+	return s
+}
+
+// operation map s.typ to Operation
+func (s *SQL) operation() Operation {
+	switch s.typ {
+	case clause.ClauseInsert, clause.ClauseInsertSelect:
+		return OpCreate
+	case clause.ClauseUpdate:
+		return OpUpdate
+	case clause.ClauseDelete:
+		return OpDelete
+	case clause.ClauseSelect:
+		return OpFind
+	}
+
+	return OpExec
+}
+
+// table main table of s
+func (s *SQL) table() string {
+	var t clause.Table
+
+	switch v := s.Clauses[s.typ].(type) {
+	case *clause.Insert:
+		t = v.Table
+	case *clause.InsertSelect:
+		t = v.Table
+	case *clause.Update:
+		t = v.Table
+	case *clause.Delete:
+		t = v.Table
+	default:
+		if f, ok := s.Clauses[clause.ClauseFrom].(*clause.From); ok && len(f.Tables) > 0 {
+			t = f.Tables[0]
+		}
+	}
+
+	if t.Name == "" && s.model != nil {
+		if mt, err := schema.ModelType(s.model); err == nil {
+			t.Name, _ = schema.TableName(mt)
+		}
+	}
+
+	return t.Name
+}
+
+// modelSchema schema to validate the columns of s, it is the model of Select/From or the model of dest.
+// it is nil unless s is a SELECT of the single table of the model, since columns of other tables are unknown.
+func (s *SQL) modelSchema(dest interface{}) *schema.Schema {
+	if len(s.compounds) > 0 || s.Clauses[clause.ClauseWith] != nil {
+		return nil
+	}
+
+	f, ok := s.Clauses[clause.ClauseFrom].(*clause.From)
+	if s.typ != clause.ClauseSelect || !ok || len(f.Tables) != 1 || len(f.Joins) > 0 {
+		return nil
+	}
+	t := f.Tables[0]
+
+	model := s.model
+	if model == nil {
+		model = dest
+	}
+	if model == nil {
+		return nil
+	}
+
+	sc, err := schema.Parse(model, s.l.opts.nameMapper)
+	if err != nil || (t.Name != sc.RawName && t.Name != sc.DBName) {
+		return nil
+	}
+
+	return sc
+}
+
+// event build s with the schema of its model or dest, see modelSchema()
+func (s *SQL) event(dest interface{}) (*QueryEvent, error) {
+	if s.l == nil {
+		return nil, ErrUnboundSQL
+	}
+
+	query, args, err := s.Build(s.l, s.modelSchema(dest), 128)
+	if err != nil {
+		return nil, err
+	}
+
+	return &QueryEvent{Op: s.operation(), Table: s.table(), SQL: query, Args: args}, nil
+}
+
+// Exec build and exec s
+func (s *SQL) Exec(ctx context.Context) (sql.Result, error) {
+	ev, err := s.event(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.l.execContext(ctx, ev)
+}
+
+// Rows build and query s
+func (s *SQL) Rows(ctx context.Context) *Rows {
+	return s.rows(ctx, nil)
+}
+
+func (s *SQL) rows(ctx context.Context, dest interface{}) *Rows {
+	ev, err := s.event(dest)
+	if err != nil {
+		return &Rows{err: err, l: s.l}
+	}
+
+	return s.l.queryContext(ctx, ev)
+}
+
+// All query s and scan all rows into dest, see (*Rows) All()
+func (s *SQL) All(ctx context.Context, dest interface{}) error {
+	return s.rows(ctx, dest).All(dest)
+}
+
+// One query s and scan the first row into dest, see (*Rows) One()
+func (s *SQL) One(ctx context.Context, dest interface{}) (bool, error) {
+	return s.rows(ctx, dest).One(dest)
+}
+
+// Scalar query s and scan the single column of the first row into dest, returns ErrNoRows if no row
+func (s *SQL) Scalar(ctx context.Context, dest interface{}) error {
+	ok, err := s.One(ctx, dest)
+	if err == nil && !ok {
+		return ErrNoRows
+	}
+
+	return err
+}
@@ -0,0 +1,113 @@
+package layer
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/meilihao/layer/clause"
+	"github.com/stretchr/testify/assert"
+)
+
+type execUser struct {
+	Id       int `layer:";pk;autoincr"`
+	UserName string
+}
+
+func TestBuilder_Model(t *testing.T) {
+	b := Select(&execUser{}).From(&execUser{}).Where(clause.Eq("Id", 1))
+	sql, args, err := b.Build(l, nil, 0)
+	assert.NoError(t, err)
+	assert.EqualValues(t, "SELECT `id`,`user_name` FROM `exec_user` WHERE `id` = ?", sql)
+	assert.EqualValues(t, []interface{}{1}, args)
+	assert.EqualValues(t, OpFind, b.operation())
+	assert.EqualValues(t, "execUser", b.table())
+
+	b = Select("Id", clause.Expr{Sql: "count(*)"}).From([]*execUser{})
+	sql, _, err = b.Build(l, nil, 0)
+	assert.NoError(t, err)
+	assert.EqualValues(t, "SELECT `id`,count(*) FROM `exec_user`", sql)
+
+	b = Update("table1").Set(map[string]interface{}{"A": 1})
+	assert.EqualValues(t, OpUpdate, b.operation())
+	assert.EqualValues(t, "table1", b.table())
+}
+
+func TestBuilder_Unbound(t *testing.T) {
+	b := Select("Id").From("t1")
+
+	_, err := b.Exec(context.Background())
+	assert.EqualValues(t, ErrUnboundSQL, err)
+
+	var ids []int
+	assert.EqualValues(t, ErrUnboundSQL, b.All(context.Background(), &ids))
+
+	var n int
+	assert.EqualValues(t, ErrUnboundSQL, b.Scalar(context.Background(), &n))
+
+	// build error is returned before query
+	b = Select("Id").From(1).Bind(l)
+	_, err = b.One(context.Background(), &n)
+	assert.True(t, errors.Is(err, ErrNoSupportedInput))
+}
+
+func TestBuilder_Exec(t *testing.T) {
+	sl := newSQLite(t)
+	ctx := context.Background()
+
+	_, err := sl.Exec("CREATE TABLE exec_user (id INTEGER PRIMARY KEY AUTOINCREMENT, user_name TEXT)")
+	assert.NoError(t, err)
+
+	for _, name := range []string{"a", "b", "c"} {
+		res, err := Insert("exec_user").Values(map[string]interface{}{"UserName": name}).Bind(sl).Exec(ctx)
+		assert.NoError(t, err)
+		n, _ := res.RowsAffected()
+		assert.EqualValues(t, 1, n)
+	}
+
+	res, err := Update("exec_user").Set(map[string]interface{}{"UserName": "x"}).Where(clause.Eq("Id", 3)).Bind(sl).Exec(ctx)
+	assert.NoError(t, err)
+	n, _ := res.RowsAffected()
+	assert.EqualValues(t, 1, n)
+
+	var us []*execUser
+	assert.NoError(t, Select(&execUser{}).From(&execUser{}).Where(clause.Gt("Id", 1)).OrderBy("Id").Bind(sl).All(ctx, &us))
+	assert.EqualValues(t, []*execUser{{Id: 2, UserName: "b"}, {Id: 3, UserName: "x"}}, us)
+
+	var u execUser
+	ok, err := Select("Id", "UserName").From("exec_user").Where(clause.Eq("Id", 1)).Bind(sl).One(ctx, &u)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.EqualValues(t, execUser{Id: 1, UserName: "a"}, u)
+
+	ok, err = Select("Id", "UserName").From("exec_user").Where(clause.Eq("Id", 9)).Bind(sl).One(ctx, &u)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	var count int
+	assert.NoError(t, Select(clause.Expr{Sql: "count(*)"}).From("exec_user").Bind(sl).Scalar(ctx, &count))
+	assert.EqualValues(t, 3, count)
+
+	// columns are validated by the model of From or dest, sub queries by their own tables
+	err = Select(&execUser{}).From(&execUser{}).Where(clause.Eq("Name", "a")).Bind(sl).All(ctx, &us)
+	assert.True(t, errors.Is(err, ErrNoColumn))
+
+	_, err = Select("Id", "Age").From("exec_user").Bind(sl).One(ctx, &u)
+	assert.True(t, errors.Is(err, ErrNoColumn))
+
+	_, err = sl.Exec("CREATE TABLE exec_tag (user_id INTEGER, tag TEXT)")
+	assert.NoError(t, err)
+	_, err = sl.Exec("INSERT INTO exec_tag (user_id, tag) VALUES (3, 'vip')")
+	assert.NoError(t, err)
+
+	us = nil
+	assert.NoError(t, Select(&execUser{}).From(&execUser{}).Where(clause.Expr{Sql: "id IN (?)", Args: []interface{}{
+		Select("UserId").From("exec_tag").Where(clause.Eq("Tag", "vip")),
+	}}).Bind(sl).All(ctx, &us))
+	assert.EqualValues(t, []*execUser{{Id: 3, UserName: "x"}}, us)
+
+	res, err = Delete("exec_user").Where(clause.Lt("Id", 3)).Bind(sl).Exec(ctx)
+	assert.NoError(t, err)
+	n, _ = res.RowsAffected()
+	assert.EqualValues(t, 2, n)
+}
@@ -0,0 +1,75 @@
+package layer
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/meilihao/layer/clause"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuilder_Func(t *testing.T) {
+	cases := []struct {
+		e                 clause.Expression
+		mysql, pg, sqlite string
+	}{
+		{clause.Coalesce("Nick", "Name", clause.Val("-")), "COALESCE(`nick`,`name`,?)", `COALESCE("nick","name",$1)`, `COALESCE("nick","name",?)`},
+		{clause.Lower("Name").As("N"), "LOWER(`name`) AS `n`", `LOWER("name") AS "n"`, `LOWER("name") AS "n"`},
+		{clause.Length("Name"), "CHAR_LENGTH(`name`)", `CHAR_LENGTH("name")`, `LENGTH("name")`},
+		{clause.Concat("First", clause.Val(" "), "Last"), "CONCAT(`first`,?,`last`)", `CONCAT("first",$1,"last")`, `("first" || ? || "last")`},
+		{clause.Substring("Name", 2, 3), "SUBSTRING(`name`,?,?)", `SUBSTRING("name" FROM $1 FOR $2)`, `SUBSTR("name",?,?)`},
+		{clause.Substring("Name", 2), "SUBSTRING(`name`,?)", `SUBSTRING("name" FROM $1)`, `SUBSTR("name",?)`},
+		{clause.Round("Price", 2), "ROUND(`price`,2)", `ROUND(CAST("price" AS NUMERIC),2)`, `ROUND("price",2)`},
+		{clause.NullIf("A", nil), "NULLIF(`a`,NULL)", `NULLIF("a",NULL)`, `NULLIF("a",NULL)`},
+		{clause.Cast("Age", clause.CastText), "CAST(`age` AS CHAR)", `CAST("age" AS TEXT)`, `CAST("age" AS TEXT)`},
+		{clause.Cast("Created", clause.CastDate), "CAST(`created` AS DATE)", `CAST("created" AS DATE)`, `DATE("created")`},
+		{clause.Now(), "CURRENT_TIMESTAMP", "CURRENT_TIMESTAMP", "CURRENT_TIMESTAMP"},
+		{clause.DateAdd("Created", 3, clause.UnitDay), "DATE_ADD(`created`,INTERVAL ? DAY)", `("created" + MAKE_INTERVAL(days => $1))`, `DATETIME("created",? || ' days')`},
+		{clause.DateSub(clause.Now(), 1, clause.UnitHour), "DATE_SUB(CURRENT_TIMESTAMP,INTERVAL ? HOUR)", `(CURRENT_TIMESTAMP - MAKE_INTERVAL(hours => $1))`, `DATETIME(CURRENT_TIMESTAMP,'-' || ? || ' hours')`},
+		{clause.DateTrunc(clause.UnitMonth, "Created"), "CAST(DATE_FORMAT(`created`,'%Y-%m-01') AS DATETIME)", `DATE_TRUNC('month',"created")`, `DATETIME("created",'start of month')`},
+		{clause.CountDistinct("UserId"), "COUNT(DISTINCT `user_id`)", `COUNT(DISTINCT "user_id")`, `COUNT(DISTINCT "user_id")`},
+		// raw functions are written as is
+		{clause.Fn("LENGTH", "Name"), "LENGTH(`name`)", `LENGTH("name")`, `LENGTH("name")`},
+		{clause.Func{Name: "NOW"}, "NOW()", "NOW()", "NOW()"},
+		{clause.Fn("SUBSTRING", "Name", 2), "SUBSTRING(`name`,?)", `SUBSTRING("name",$1)`, `SUBSTRING("name",?)`},
+		{clause.Sum(clause.Case().When(clause.Gt("Amount", 100), 1).Else(0)),
+			"SUM(CASE WHEN `amount` > ? THEN ? ELSE ? END)", `SUM(CASE WHEN "amount" > $1 THEN $2 ELSE $3 END)`, `SUM(CASE WHEN "amount" > ? THEN ? ELSE ? END)`},
+		{clause.Case("Status").When(1, "active").When(2, clause.Column{Name: "Reason"}).As("S"),
+			"CASE `status` WHEN ? THEN ? WHEN ? THEN `reason` END AS `s`", `CASE "status" WHEN $1 THEN $2 WHEN $3 THEN "reason" END AS "s"`, `CASE "status" WHEN ? THEN ? WHEN ? THEN "reason" END AS "s"`},
+	}
+
+	for _, c := range cases {
+		for _, v := range []struct {
+			l   *Layer
+			sql string
+		}{{l, c.mysql}, {pg, c.pg}, {lite, c.sqlite}} {
+			b := NewSQLBuilder(v.l, nil, 0)
+			assert.NoError(t, c.e.Build(b))
+			assert.EqualValues(t, v.sql, b.String())
+		}
+	}
+
+	// usable in Select, Where, OrderBy and Set
+	b := Select("Id", clause.Upper("Name").As("Name")).From("user1").
+		Where(clause.Expr{Sql: "? > ?", Args: []interface{}{clause.DateAdd("Created", 7, clause.UnitDay), clause.Now()}}).
+		OrderBy(clause.Coalesce("Nick", "Name"))
+	sql, args, err := b.Build(pg, nil, 0)
+	assert.NoError(t, err)
+	assert.EqualValues(t, `SELECT "id",UPPER("name") AS "name" FROM "user1" WHERE ("created" + MAKE_INTERVAL(days => $1)) > CURRENT_TIMESTAMP ORDER BY COALESCE("nick","name")`, sql)
+	assert.EqualValues(t, []interface{}{7}, args)
+
+	sql, args, err = Update("user1").Set(clause.Assignment{Column: clause.Column{Name: "Name"}, Value: clause.Trim("Name")}).
+		Where(clause.Eq("Id", 1)).Build(l, nil, 0)
+	assert.NoError(t, err)
+	assert.EqualValues(t, "UPDATE `user1` SET `name`=TRIM(`name`) WHERE `id` = ?", sql)
+	assert.EqualValues(t, []interface{}{1}, args)
+
+	_, _, err = Select(clause.Cast("Age", "JSON")).From("user1").Build(l, nil, 0)
+	assert.True(t, errors.Is(err, clause.ErrUnsupportedFunc))
+
+	_, _, err = Select(clause.DateTrunc(clause.UnitSecond, "Created")).From("user1").Build(l, nil, 0)
+	assert.True(t, errors.Is(err, clause.ErrUnsupportedFunc))
+
+	_, _, err = Select(clause.Case()).From("user1").Build(l, nil, 0)
+	assert.Equal(t, clause.ErrEmptyCase, err)
+}
@@ -0,0 +1,87 @@
+package layer
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/meilihao/layer/clause"
+	"github.com/stretchr/testify/assert"
+)
+
+type jsonDoc struct {
+	Id    int                    `layer:";pk"`
+	Attrs map[string]interface{} `layer:";json"`
+}
+
+func TestBuilder_JSON(t *testing.T) {
+	type result struct {
+		sql  string
+		args []interface{}
+	}
+	cases := []struct {
+		e                 clause.Expression
+		mysql, pg, sqlite result
+	}{
+		{
+			clause.JSONExtract("Attrs", "size", 0),
+			result{"JSON_EXTRACT(`attrs`,?)", []interface{}{"$.size[0]"}},
+			result{`("attrs" #> CAST($1 AS TEXT[]))`, []interface{}{`{"size","0"}`}},
+			result{`JSON_EXTRACT("attrs",?)`, []interface{}{"$.size[0]"}},
+		},
+		{
+			clause.JSONExtractText("Attrs", "a b"),
+			result{"JSON_UNQUOTE(JSON_EXTRACT(`attrs`,?))", []interface{}{`$."a b"`}},
+			result{`("attrs" #>> CAST($1 AS TEXT[]))`, []interface{}{`{"a b"}`}},
+			result{`JSON_EXTRACT("attrs",?)`, []interface{}{`$."a b"`}},
+		},
+		{
+			clause.JSONHasKey("Attrs", "color"),
+			result{"JSON_CONTAINS_PATH(`attrs`,'one',?)", []interface{}{"$.color"}},
+			result{`(("attrs" #> CAST($1 AS TEXT[])) IS NOT NULL)`, []interface{}{`{"color"}`}},
+			result{`(JSON_TYPE("attrs",?) IS NOT NULL)`, []interface{}{"$.color"}},
+		},
+		{
+			clause.JSONArrayContains("Attrs", "red", "tags"),
+			result{"JSON_CONTAINS(`attrs`,?,?)", []interface{}{`["red"]`, "$.tags"}},
+			result{`(("attrs" #> CAST($1 AS TEXT[])) @> CAST($2 AS JSONB))`, []interface{}{`{"tags"}`, `["red"]`}},
+			result{`EXISTS(SELECT 1 FROM JSON_EACH("attrs",?) WHERE JSON_EACH.value = JSON_EXTRACT(?,'$[0]'))`, []interface{}{"$.tags", `["red"]`}},
+		},
+		{
+			clause.JSONSet("Attrs", 3, "size"),
+			result{"JSON_SET(`attrs`,?,CAST(? AS JSON))", []interface{}{"$.size", "3"}},
+			result{`JSONB_SET("attrs",CAST($1 AS TEXT[]),CAST($2 AS JSONB))`, []interface{}{`{"size"}`, "3"}},
+			result{`JSON_SET("attrs",?,JSON(?))`, []interface{}{"$.size", "3"}},
+		},
+	}
+
+	for _, c := range cases {
+		for _, v := range []struct {
+			l *Layer
+			r result
+		}{{l, c.mysql}, {pg, c.pg}, {lite, c.sqlite}} {
+			b := NewSQLBuilder(v.l, nil, 0)
+			assert.NoError(t, c.e.Build(b))
+			assert.EqualValues(t, v.r.sql, b.String())
+			assert.EqualValues(t, v.r.args, b.Args)
+		}
+	}
+
+	b := Select("Id").From("json_doc").Where(clause.JSONContains("Attrs", map[string]interface{}{"color": "red"})).
+		OrderBy(clause.JSONExtract("Attrs", "size"))
+	sql, args, err := b.Build(pg, nil, 0)
+	assert.NoError(t, err)
+	assert.EqualValues(t, `SELECT "id" FROM "json_doc" WHERE ("attrs" @> CAST($1 AS JSONB)) ORDER BY ("attrs" #> CAST($2 AS TEXT[]))`, sql)
+	assert.EqualValues(t, []interface{}{`{"color":"red"}`, `{"size"}`}, args)
+
+	_, _, err = Select("Id").From("json_doc").Where(clause.JSONContains("Attrs", 1)).Build(lite, nil, 0)
+	assert.True(t, errors.Is(err, clause.ErrUnsupportedFunc))
+
+	// args of expressions are bound to their columns
+	se := l.NewUpdateSession().DryRun().Set(map[string]interface{}{"Attrs": clause.JSONSet("Attrs", "red", "color")})
+	_, err = se.Update(&jsonDoc{Id: 1})
+	assert.NoError(t, err)
+	assert.EqualValues(t, "UPDATE `json_doc` SET `attrs`=JSON_SET(`attrs`,?,CAST(? AS JSON)) WHERE `id` = ?", se.builder.String())
+	assert.EqualValues(t, []interface{}{"$.color", `"red"`, nil}, se.builder.Args)
+	assert.EqualValues(t, 3, len(se.builder.ArgColumns))
+	assert.EqualValues(t, "Id", se.builder.ArgColumns[2].RawName)
+}
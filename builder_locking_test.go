@@ -0,0 +1,53 @@
+package layer
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/meilihao/layer/clause"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuilder_Locking(t *testing.T) {
+	b := Select("Id").From("job").Where(clause.Eq("Status", 0)).OrderBy("Id").Limit(10).ForUpdate(clause.LockSkipLocked)
+	sql, _, err := b.Build(l, nil, 0)
+	assert.NoError(t, err)
+	assert.EqualValues(t, "SELECT `id` FROM `job` WHERE `status` = ? ORDER BY `id` ASC LIMIT 10 FOR UPDATE SKIP LOCKED", sql)
+
+	b = Select("Id").From("job").ForShare(clause.LockNoWait)
+	sql, _, err = b.Build(l, nil, 0)
+	assert.NoError(t, err)
+	assert.EqualValues(t, "SELECT `id` FROM `job` FOR SHARE NOWAIT", sql)
+
+	b = Select("Id").From("job").Lock(clause.Locking{Strength: clause.LockNoKeyUpdate}.Of("job").SkipLocked())
+	sql, _, err = b.Build(pg, nil, 0)
+	assert.NoError(t, err)
+	assert.EqualValues(t, `SELECT "id" FROM "job" FOR NO KEY UPDATE OF "job" SKIP LOCKED`, sql)
+
+	_, _, err = b.Build(l, nil, 0)
+	assert.True(t, errors.Is(err, ErrUnsupportedLocking))
+
+	_, _, err = Select("Id").From("job").Lock(clause.Locking{Strength: "EXCLUSIVE"}).Build(pg, nil, 0)
+	assert.True(t, errors.Is(err, ErrUnsupportedLocking))
+
+	_, _, err = Select("Id").From("job").ForUpdate().Build(lite, nil, 0)
+	assert.True(t, errors.Is(err, ErrUnsupportedLocking))
+
+	var be *clause.BuildError
+	assert.True(t, errors.As(err, &be))
+	assert.EqualValues(t, clause.ClauseLocking, be.Clause)
+
+	// invalid option is reported the same way
+	_, _, err = Select("Id").From("job").Lock(clause.Locking{Strength: clause.LockUpdate, Option: "WAIT 5"}).Build(l, nil, 0)
+	assert.True(t, errors.Is(err, ErrUnsupportedLocking))
+	assert.True(t, errors.As(err, &be))
+	assert.EqualValues(t, clause.ClauseLocking, be.Clause)
+
+	se := l.NewFindSession().DryRun().ForUpdate(clause.LockNoWait)
+	_, err = se.Find(&execUser{Id: 1})
+	assert.NoError(t, err)
+	assert.EqualValues(t, "SELECT `id`,`user_name` FROM `exec_user` WHERE `id` = ? FOR UPDATE NOWAIT", se.builder.String())
+
+	_, err = lite.NewFindSession().DryRun().ForShare().Find(&execUser{Id: 1})
+	assert.True(t, errors.Is(err, ErrUnsupportedLocking))
+}
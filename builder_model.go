@@ -0,0 +1,306 @@
+package layer
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/meilihao/layer/clause"
+	"github.com/meilihao/layer/schema"
+)
+
+var (
+	ErrNoJoinRelationship = errors.New("layer : no many2one relationship to join")
+)
+
+// sqlModel a model used by From or Join, it is parsed when building, see parseModel
+type sqlModel struct {
+	model interface{}
+	name  string // alias or raw table name, used to qualify its columns
+	join  int    // index in From.Joins, -1 for From.Tables
+}
+
+// addModel record model of From or Join, table is its raw table name
+func (s *SQL) addModel(model interface{}, table, alias string, join int) {
+	name := table
+	if alias != "" {
+		name = alias
+	}
+
+	s.models = append(s.models, sqlModel{model: model, name: name, join: join})
+}
+
+// parseModel parse model with the NameMapper of the Layer which builder belongs to
+func parseModel(builder clause.Builder, model interface{}) (*schema.Schema, error) {
+	var namer schema.NameMapper = schema.SnakeNameMapper{}
+	if b, ok := builder.(*SQLBuilder); ok && b.l != nil {
+		namer = b.l.opts.nameMapper
+	}
+
+	return schema.Parse(model, namer)
+}
+
+// modelColumns all columns of a model passed to Select
+type modelColumns struct {
+	model interface{}
+}
+
+func (e modelColumns) Build(builder clause.Builder) error {
+	sc, err := parseModel(builder, e.model)
+	if err != nil {
+		return err
+	}
+
+	for idx, c := range sc.Columns {
+		if idx > 0 {
+			builder.WriteByte(',')
+		}
+		builder.WriteQuoted(clause.Column{Name: c.RawName})
+	}
+
+	return nil
+}
+
+// JoinModel join model with ON derived from the many2one relationship between it and a model added before,
+// in either direction. Join(model) is JoinModel(clause.JoinInner, model). CROSS JOIN has no ON.
+func (s *SQL) JoinModel(typ clause.JoinType, model interface{}, alias ...string) *SQL {
+	s = s.cow()
+
+	t, ok := s.modelTable(model)
+	if !ok {
+		s.err = fmt.Errorf("%w: join %T", ErrNoSupportedInput, model)
+
+		return s
+	}
+
+	j := &clause.Join{Type: typ, Table: t}
+	if len(alias) > 0 {
+		j.Table.Alias = alias[0]
+	}
+
+	m := sqlModel{model: model, name: t.Name}
+	if j.Table.Alias != "" {
+		m.name = j.Table.Alias
+	}
+
+	if typ != clause.JoinCross {
+		j.ON.Exprs = []clause.Expression{joinModelOn{models: append([]sqlModel(nil), s.models...), model: m}}
+	}
+
+	return s.Join(j).addJoinModel(m)
+}
+
+func (s *SQL) addJoinModel(m sqlModel) *SQL {
+	from := s.Clauses[clause.ClauseFrom].(*clause.From)
+	m.join = len(from.Joins) - 1
+	s.models = append(s.models, m)
+
+	return s
+}
+
+// joinModelOn ON of JoinModel, it is derived when building since the models are parsed then
+type joinModelOn struct {
+	models []sqlModel // models added before
+	model  sqlModel
+}
+
+func (e joinModelOn) Build(builder clause.Builder) error {
+	sc, err := parseModel(builder, e.model.model)
+	if err != nil {
+		return err
+	}
+
+	for _, v := range e.models {
+		vsc, err := parseModel(builder, v.model)
+		if err != nil {
+			return err
+		}
+
+		on := joinOn(v.name, vsc, e.model.name, sc)
+		if len(on) == 0 {
+			on = joinOn(e.model.name, sc, v.name, vsc)
+		}
+		if len(on) == 0 {
+			continue
+		}
+
+		for idx, c := range on {
+			if idx > 0 {
+				builder.WriteString(" AND ")
+			}
+			if err = c.Build(builder); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	return fmt.Errorf("%w: %s", ErrNoJoinRelationship, sc.Name)
+}
+
+// joinOn `child.fk = parent.pk` by the first many2one field of child which refers to parent,
+// childName and parentName qualify their columns
+func joinOn(childName string, child *schema.Schema, parentName string, parent *schema.Schema) []clause.Expression {
+	var (
+		on  []clause.Expression
+		rel *schema.Field
+	)
+
+	for _, c := range child.Columns {
+		p := c.Parent
+		if p == nil || p.Relationship == nil || p.Relationship.Type != schema.RelMany2One ||
+			p.Relationship.Schema.ModelType != parent.ModelType {
+			continue
+		}
+
+		if rel == nil {
+			rel = p
+		} else if p != rel {
+			break
+		}
+
+		// c.Field is the primary key field of parent
+		on = append(on, columnEq{
+			left:  clause.Column{Table: childName, Name: c.RawName},
+			right: clause.Column{Table: parentName, Name: c.Field.RawName},
+		})
+	}
+
+	return on
+}
+
+// columnEq `left = right` of two columns
+type columnEq struct {
+	left, right clause.Column
+}
+
+func (e columnEq) Build(builder clause.Builder) error {
+	builder.WriteQuoted(e.left)
+	builder.WriteString(" = ")
+	builder.WriteQuoted(e.right)
+
+	return nil
+}
+
+// Strict validate column names against the models of From and Join when building, qualified names are checked
+// against the model of the table or alias. unqualified names are checked only if all tables are models,
+// so use clause.Expr for select aliases.
+func (s *SQL) Strict() *SQL {
+	s = s.cow()
+	s.strict = true
+
+	return s
+}
+
+// SoftDelete filter out soft-deleted rows of every model which has a deleted_at column,
+// it goes to WHERE for the models of From and to ON for the joined ones. only for SELECT.
+func (s *SQL) SoftDelete() *SQL {
+	s = s.cow()
+	s.softDelete = true
+
+	return s
+}
+
+// softDeleteClauses copy of s.Clauses with soft-delete conditions
+func (s *SQL) softDeleteClauses(builder *SQLBuilder) (clause.Clauses, error) {
+	cs := s.Clauses
+	copied := false
+
+	for _, m := range s.models {
+		sc, err := parseModel(builder, m.model)
+		if err != nil {
+			return nil, err
+		}
+		if sc.DeletedAt == nil {
+			continue
+		}
+
+		if !copied {
+			cs = make(clause.Clauses, len(s.Clauses)+1)
+			for k, v := range s.Clauses {
+				cs[k] = v
+			}
+			from := *(s.Clauses[clause.ClauseFrom].(*clause.From))
+			from.Joins = append([]clause.Join(nil), from.Joins...)
+			cs[clause.ClauseFrom] = &from
+
+			w := &clause.Where{}
+			if v, ok := s.Clauses[clause.ClauseWhere].(*clause.Where); ok {
+				w.Exprs = append(w.Exprs, v.Exprs...)
+			}
+			cs[clause.ClauseWhere] = w
+			copied = true
+		}
+
+		e := clause.IsNULL(m.name + "." + sc.DeletedAt.RawName)
+		if m.join < 0 {
+			w := cs[clause.ClauseWhere].(*clause.Where)
+			w.Exprs = append(w.Exprs, e)
+		} else {
+			from := cs[clause.ClauseFrom].(*clause.From)
+			j := &from.Joins[m.join]
+			j.ON.Exprs = append(j.ON.Exprs[:len(j.ON.Exprs):len(j.ON.Exprs)], e)
+		}
+	}
+
+	return cs, nil
+}
+
+// useModels validate columns against the models of s while building it if s is strict,
+// returns the function to restore the ones of the outer sql
+func (b *SQLBuilder) useModels(s *SQL) (func(), error) {
+	models, all := b.models, b.allModels
+	restore := func() {
+		b.models, b.allModels = models, all
+	}
+
+	if !s.strict {
+		b.models = nil
+
+		return restore, nil
+	}
+
+	m := make(map[string]*schema.Schema, len(s.models))
+	for _, v := range s.models {
+		sc, err := parseModel(b, v.model)
+		if err != nil {
+			return nil, err
+		}
+		m[v.name] = sc
+	}
+	b.models = m
+
+	b.allModels = false
+	if from, ok := s.Clauses[clause.ClauseFrom].(*clause.From); ok {
+		b.allModels = len(s.models) == len(from.Tables)+len(from.Joins)
+	}
+
+	return restore, nil
+}
+
+// checkModelColumn validate v against b.models, see (*SQL) Strict()
+func (b *SQLBuilder) checkModelColumn(v clause.Column) {
+	if v.Name == "*" {
+		return
+	}
+
+	if v.Table != "" {
+		if sc := b.models[v.Table]; sc != nil && sc.ColumnsByRawName[v.Name] == nil {
+			b.AddError(&clause.BuildError{Column: v.Table + "." + v.Name, Err: fmt.Errorf("%w in %s", ErrNoColumn, sc.Name)})
+		}
+
+		return
+	}
+
+	if !b.allModels {
+		return
+	}
+
+	for _, sc := range b.models {
+		if sc.ColumnsByRawName[v.Name] != nil {
+			return
+		}
+	}
+
+	b.AddError(&clause.BuildError{Column: v.Name, Err: ErrNoColumn})
+}
@@ -0,0 +1,96 @@
+package layer
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/meilihao/layer/clause"
+	"github.com/meilihao/layer/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+type modelDept struct {
+	Id        int `layer:";pk"`
+	Name      string
+	DeletedAt int64 `layer:";deleted_at"`
+}
+
+type modelEmp struct {
+	Id        int `layer:";pk"`
+	Name      string
+	Dept      *modelDept `layer:";many2one"`
+	Manager   *modelEmp  `layer:";many2one"`
+	DeletedAt int64      `layer:";deleted_at"`
+}
+
+func TestBuilder_ModelJoin(t *testing.T) {
+	b := Select("e.Name", "d.Name").From(&modelEmp{}, "e").JoinModel(clause.JoinLeft, &modelDept{}, "d").Where(clause.Eq("e.Id", 1))
+	sql, args, err := b.Build(l, nil, 0)
+	assert.NoError(t, err)
+	assert.EqualValues(t, "SELECT `e`.`name`,`d`.`name` FROM `model_emp` AS `e` LEFT JOIN `model_dept` AS `d` ON `e`.`dept_id` = `d`.`id` WHERE `e`.`id` = ?", sql)
+	assert.EqualValues(t, []interface{}{1}, args)
+
+	// parent first, ON is derived from the relationship of the joined child
+	b = Select("modelDept.Name").From(&modelDept{}).Join(&modelEmp{})
+	sql, _, err = b.Build(l, nil, 0)
+	assert.NoError(t, err)
+	assert.EqualValues(t, "SELECT `model_dept`.`name` FROM `model_dept` INNER JOIN `model_emp` ON `model_emp`.`dept_id` = `model_dept`.`id`", sql)
+
+	// self join by alias
+	b = Select("e.Name", "m.Name").From(&modelEmp{}, "e").JoinModel(clause.JoinLeft, &modelEmp{}, "m").SoftDelete()
+	sql, _, err = b.Build(l, nil, 0)
+	assert.NoError(t, err)
+	assert.EqualValues(t, "SELECT `e`.`name`,`m`.`name` FROM `model_emp` AS `e` LEFT JOIN `model_emp` AS `m` ON `e`.`manager_id` = `m`.`id` AND `m`.`deleted_at` IS NULL WHERE `e`.`deleted_at` IS NULL", sql)
+	// soft-delete conditions are added while building only
+	assert.EqualValues(t, 1, len(b.Clauses[clause.ClauseFrom].(*clause.From).Joins[0].ON.Exprs))
+	assert.Nil(t, b.Clauses[clause.ClauseWhere])
+
+	_, _, err = Select("Id").From("t1").Join(&modelDept{}).Build(l, nil, 0)
+	assert.True(t, errors.Is(err, ErrNoJoinRelationship))
+
+	// CROSS JOIN has no ON
+	sql, _, err = Select("modelDept.Name").From(&modelDept{}).JoinModel(clause.JoinCross, &modelEmp{}).Build(l, nil, 0)
+	assert.NoError(t, err)
+	assert.EqualValues(t, "SELECT `model_dept`.`name` FROM `model_dept` CROSS JOIN `model_emp`", sql)
+}
+
+func TestBuilder_ModelStrict(t *testing.T) {
+	b := Select("e.Name", "d.Nmae", "Age").From(&modelEmp{}, "e").Join(&modelDept{}).Where(clause.Eq("DeptId", 1)).Strict()
+	_, _, err := b.Build(l, nil, 0)
+	// d is not an alias of the models, so d.Nmae is not checked
+	assert.True(t, errors.Is(err, ErrNoColumn))
+	assert.EqualValues(t, "layer : build clause SELECT column Age: no column", err.Error())
+
+	b = Select("e.Name", "modelDept.Nmae").From(&modelEmp{}, "e").Join(&modelDept{}).Strict()
+	_, _, err = b.Build(l, nil, 0)
+	assert.True(t, errors.Is(err, ErrNoColumn))
+	assert.EqualValues(t, "layer : build clause SELECT column modelDept.Nmae: no column in modelDept", err.Error())
+
+	// unqualified columns are not checked with a raw table, sub queries use their own models
+	sub := Select("Id").From(&modelDept{}).Where(clause.Eq("Name", "a"))
+	b = Select("e.Name", "x.Age").From(&modelEmp{}, "e").Join(clause.InnerJoin("x").On(clause.Expr{Sql: "x.id = e.id"})).
+		Where(clause.Eq("Age", 1), clause.Expr{Sql: "e.dept_id IN (?)", Args: []interface{}{sub}}).Strict()
+	sql, _, err := b.Build(l, nil, 0)
+	assert.NoError(t, err)
+	assert.EqualValues(t, "SELECT `e`.`name`,`x`.`age` FROM `model_emp` AS `e` INNER JOIN `x` ON x.id = e.id WHERE `age` = ? AND e.dept_id IN (SELECT `id` FROM `model_dept` WHERE `name` = ?)", sql)
+}
+
+type mapperUser struct {
+	Id       int `layer:";pk"`
+	UserName string
+}
+
+func TestBuilder_ModelNameMapper(t *testing.T) {
+	// an unbound sql only records the model, which is parsed with the NameMapper of the Layer when building
+	b := Select(&mapperUser{}).From(&mapperUser{}).Where(clause.Eq("UserName", "a")).Strict()
+
+	sl := *l
+	sl.opts.nameMapper = schema.SameNameMapper{}
+	sql, _, err := b.Build(&sl, nil, 0)
+	assert.NoError(t, err)
+	assert.EqualValues(t, "SELECT `Id`,`UserName` FROM `mapperUser` WHERE `UserName` = ?", sql)
+
+	sc, err := schema.Parse(&mapperUser{}, l.opts.nameMapper)
+	assert.NoError(t, err)
+	assert.EqualValues(t, "UserName", sc.ColumnsByRawName["UserName"].DBName)
+}
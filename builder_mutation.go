@@ -0,0 +1,167 @@
+package layer
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/meilihao/layer/clause"
+	"github.com/meilihao/layer/dialect"
+)
+
+var (
+	ErrUnsupportedMultiTable    = errors.New("layer : unsupported multi-table update or delete")
+	ErrUnsupportedMutationLimit = errors.New("layer : unsupported ORDER BY or LIMIT in update or delete")
+)
+
+// DeleteTargets tables whose rows are deleted by mysql multi-table DELETE, default is the table of Delete(). use alias if the table has one.
+func (s *SQL) DeleteTargets(tables ...string) *SQL {
+	s = s.cow()
+
+	e := s.Clauses[clause.ClauseDelete]
+
+	var t *clause.Delete
+	if e != nil {
+		t = e.(*clause.Delete)
+	} else {
+		t = &clause.Delete{}
+	}
+
+	for _, v := range tables {
+		t.Targets = append(t.Targets, clause.Table{Name: v})
+	}
+
+	s.Clauses[clause.ClauseDelete] = t
+
+	return s
+}
+
+// buildMutation build UPDATE or DELETE, tables added by From and Join are other tables of it
+func (s *SQL) buildMutation(builder *SQLBuilder) error {
+	main := clause.ClauseUpdate
+	if s.typ == clause.ClauseDelete {
+		main = clause.ClauseDelete
+	}
+
+	from, _ := s.Clauses[clause.ClauseFrom].(*clause.From)
+	if from == nil || len(from.Tables)+len(from.Joins) == 0 {
+		names := []string{clause.ClauseWith, main, clause.ClauseSet, clause.ClauseWhere}
+		if s.Clauses[clause.ClauseOrderBy] != nil || s.Clauses[clause.ClauseLimit] != nil {
+			if !builder.Dialect().HasMutationLimit() {
+				return fmt.Errorf("%w in %s", ErrUnsupportedMutationLimit, builder.Dialect().Dialect())
+			}
+			// mysql only accepts LIMIT row_count
+			if limit, ok := s.Clauses[clause.ClauseLimit].(*clause.Limit); ok && limit.Offset > 0 {
+				return fmt.Errorf("%w: OFFSET in %s", ErrUnsupportedMutationLimit, builder.Dialect().Dialect())
+			}
+
+			names = append(names, clause.ClauseOrderBy, clause.ClauseLimit)
+		}
+
+		return s.Clauses.Build(builder, names...)
+	}
+
+	if s.Clauses[clause.ClauseOrderBy] != nil || s.Clauses[clause.ClauseLimit] != nil {
+		return fmt.Errorf("%w with other tables", ErrUnsupportedMutationLimit)
+	}
+
+	cs := make(clause.Clauses, len(s.Clauses))
+	for k, v := range s.Clauses {
+		cs[k] = v
+	}
+
+	switch builder.Dialect().MultiTable(main) {
+	case dialect.MultiTableJoin:
+		cs[clause.ClauseFrom] = clause.From{Keyword: ",", Tables: from.Tables, Joins: from.Joins}
+
+		if main == clause.ClauseUpdate {
+			return cs.Build(builder, clause.ClauseWith, clause.ClauseUpdate, clause.ClauseFrom, clause.ClauseSet, clause.ClauseWhere)
+		}
+
+		d := *(s.Clauses[clause.ClauseDelete].(*clause.Delete))
+		if len(d.Targets) == 0 {
+			if d.Alias != "" {
+				d.Targets = []clause.Table{{Name: d.Alias}}
+			} else {
+				d.Targets = []clause.Table{{Schema: d.Schema, Name: d.Name}}
+			}
+		}
+		cs[clause.ClauseDelete] = d
+
+		return cs.Build(builder, clause.ClauseWith, clause.ClauseDelete, clause.ClauseFrom, clause.ClauseWhere)
+	case dialect.MultiTableFrom:
+		if d, ok := s.Clauses[clause.ClauseDelete].(*clause.Delete); ok && len(d.Targets) > 0 {
+			return fmt.Errorf("%w: delete targets in %s", ErrUnsupportedMultiTable, builder.Dialect().Dialect())
+		}
+
+		f := clause.From{Tables: from.Tables, Joins: from.Joins}
+		if main == clause.ClauseDelete {
+			f.Keyword = " USING "
+		}
+
+		// the target can not be joined, so the first inner join becomes a table of FROM and its ON goes to WHERE
+		var on []clause.Expression
+		if len(f.Tables) == 0 {
+			j := f.Joins[0]
+			if (j.Type != "" && j.Type != clause.JoinInner) || j.Expression != nil || len(j.USING) > 0 {
+				return fmt.Errorf("%w: %s JOIN in %s", ErrUnsupportedMultiTable, j.Type, builder.Dialect().Dialect())
+			}
+
+			f.Tables = []clause.Table{j.Table}
+			f.Joins = f.Joins[1:]
+			on = j.ON.Exprs
+		}
+		cs[clause.ClauseFrom] = f
+
+		if main == clause.ClauseUpdate {
+			set, err := s.unqualifiedSet()
+			if err != nil {
+				return fmt.Errorf("%w in %s", err, builder.Dialect().Dialect())
+			}
+			cs[clause.ClauseSet] = set
+		}
+
+		if len(on) > 0 {
+			w := clause.Where{Exprs: on}
+			if v, ok := s.Clauses[clause.ClauseWhere].(*clause.Where); ok {
+				w.Exprs = append(w.Exprs[:len(on):len(on)], v.Exprs...)
+			}
+			cs[clause.ClauseWhere] = w
+		}
+
+		return cs.Build(builder, clause.ClauseWith, main, clause.ClauseSet, clause.ClauseFrom, clause.ClauseWhere)
+	}
+
+	return fmt.Errorf("%w: %s in %s", ErrUnsupportedMultiTable, main, builder.Dialect().Dialect())
+}
+
+// unqualifiedSet SET without table qualifiers, which UPDATE ... FROM does not allow on the left side.
+// the qualifier must be the updated table or its alias.
+func (s *SQL) unqualifiedSet() (clause.Set, error) {
+	set, _ := s.Clauses[clause.ClauseSet].(*clause.Set)
+	if set == nil {
+		return nil, nil
+	}
+
+	u := s.Clauses[clause.ClauseUpdate].(*clause.Update)
+	out := make(clause.Set, 0, len(*set))
+	for _, a := range *set {
+		c := a.Column
+		if c.Table == "" {
+			if idx := strings.LastIndexByte(c.Name, '.'); idx > 0 {
+				c.Table, c.Name = c.Name[:idx], c.Name[idx+1:]
+			}
+		}
+
+		switch c.Table {
+		case "", u.Alias, u.Name, u.Schema + "." + u.Name:
+		default:
+			return nil, fmt.Errorf("%w: set column %s.%s of other table", ErrUnsupportedMultiTable, c.Table, c.Name)
+		}
+
+		c.Table = ""
+		out = append(out, clause.Assignment{Column: c, Value: a.Value})
+	}
+
+	return out, nil
+}
@@ -0,0 +1,110 @@
+package layer
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/meilihao/layer/clause"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuilder_UpdateJoin(t *testing.T) {
+	newSQL := func() *SQL {
+		return Update("order1").Join(clause.InnerJoin("user1").On(clause.Expr{Sql: "order1.user_id = user1.id"})).
+			Set(map[string]interface{}{"order1.Status": 2}).Where(clause.Eq("user1.Level", 3))
+	}
+
+	sql, args, err := newSQL().Build(l, nil, 0)
+	assert.NoError(t, err)
+	assert.EqualValues(t, "UPDATE `order1` INNER JOIN `user1` ON order1.user_id = user1.id SET `order1`.`status`=? WHERE `user1`.`level` = ?", sql)
+	assert.EqualValues(t, []interface{}{2, 3}, args)
+
+	// the join becomes FROM and its ON goes to WHERE
+	sql, args, err = newSQL().Build(pg, nil, 0)
+	assert.NoError(t, err)
+	assert.EqualValues(t, `UPDATE "order1" SET "status"=$1 FROM "user1" WHERE (order1.user_id = user1.id) AND "user1"."level" = $2`, sql)
+	assert.EqualValues(t, []interface{}{2, 3}, args)
+
+	// SET columns are not qualified, they must be of the updated table
+	sql, _, err = Update(clause.Table{Name: "order1", Alias: "o"}).From("user1").Set(map[string]interface{}{"o.Status": 2}).
+		Where(clause.Expr{Sql: "o.user_id = user1.id"}).Build(pg, nil, 0)
+	assert.NoError(t, err)
+	assert.EqualValues(t, `UPDATE "order1" AS "o" SET "status"=$1 FROM "user1" WHERE o.user_id = user1.id`, sql)
+
+	_, _, err = newSQL().Set(map[string]interface{}{"user1.Level": 1}).Build(pg, nil, 0)
+	assert.True(t, errors.Is(err, ErrUnsupportedMultiTable))
+
+	sql, _, err = Update("order1").From("user1").Set(map[string]interface{}{"Status": 2}).
+		Where(clause.Expr{Sql: "order1.user_id = user1.id"}).Build(lite, nil, 0)
+	assert.NoError(t, err)
+	assert.EqualValues(t, `UPDATE "order1" SET "status"=? FROM "user1" WHERE order1.user_id = user1.id`, sql)
+
+	sql, _, err = Update("order1").From("user1").Set(map[string]interface{}{"Status": 2}).
+		Where(clause.Expr{Sql: "order1.user_id = user1.id"}).Build(l, nil, 0)
+	assert.NoError(t, err)
+	assert.EqualValues(t, "UPDATE `order1`,`user1` SET `status`=? WHERE order1.user_id = user1.id", sql)
+
+	_, _, err = Update("order1").Join(clause.LeftJoin("user1").On(clause.Expr{Sql: "1 = 1"})).
+		Set(map[string]interface{}{"Status": 2}).Build(pg, nil, 0)
+	assert.True(t, errors.Is(err, ErrUnsupportedMultiTable))
+
+	// ORDER BY and LIMIT
+	sql, args, err = Update("order1").Set(map[string]interface{}{"Status": 2}).Where(clause.Eq("Status", 1)).
+		OrderBy("Id").Limit(10).Build(l, nil, 0)
+	assert.NoError(t, err)
+	assert.EqualValues(t, "UPDATE `order1` SET `status`=? WHERE `status` = ? ORDER BY `id` ASC LIMIT 10", sql)
+	assert.EqualValues(t, []interface{}{2, 1}, args)
+
+	_, _, err = Update("order1").Set(map[string]interface{}{"Status": 2}).Limit(10, 5).Build(l, nil, 0)
+	assert.True(t, errors.Is(err, ErrUnsupportedMutationLimit))
+
+	_, _, err = Delete("order1").Offset(5).Build(l, nil, 0)
+	assert.True(t, errors.Is(err, ErrUnsupportedMutationLimit))
+
+	_, _, err = Update("order1").Set(map[string]interface{}{"Status": 2}).Limit(10).Build(pg, nil, 0)
+	assert.True(t, errors.Is(err, ErrUnsupportedMutationLimit))
+
+	_, _, err = newSQL().Limit(10).Build(l, nil, 0)
+	assert.True(t, errors.Is(err, ErrUnsupportedMutationLimit))
+}
+
+func TestBuilder_DeleteJoin(t *testing.T) {
+	newSQL := func() *SQL {
+		return Delete("order1").Join(clause.InnerJoin("user1").On(clause.Expr{Sql: "order1.user_id = user1.id"})).
+			Where(clause.Eq("user1.Level", 3))
+	}
+
+	sql, args, err := newSQL().Build(l, nil, 0)
+	assert.NoError(t, err)
+	assert.EqualValues(t, "DELETE `order1` FROM `order1` INNER JOIN `user1` ON order1.user_id = user1.id WHERE `user1`.`level` = ?", sql)
+	assert.EqualValues(t, []interface{}{3}, args)
+
+	sql, args, err = newSQL().Build(pg, nil, 0)
+	assert.NoError(t, err)
+	assert.EqualValues(t, `DELETE FROM "order1" USING "user1" WHERE (order1.user_id = user1.id) AND "user1"."level" = $1`, sql)
+	assert.EqualValues(t, []interface{}{3}, args)
+
+	_, _, err = newSQL().Build(lite, nil, 0)
+	assert.True(t, errors.Is(err, ErrUnsupportedMultiTable))
+
+	// mysql multi-table delete
+	sql, _, err = Delete(clause.Table{Name: "order1", Alias: "o"}).From("user1", "u").DeleteTargets("o", "u").
+		Where(clause.Expr{Sql: "o.user_id = u.id"}).Build(l, nil, 0)
+	assert.NoError(t, err)
+	assert.EqualValues(t, "DELETE `o`,`u` FROM `order1` AS `o`,`user1` AS `u` WHERE o.user_id = u.id", sql)
+
+	sql, _, err = Delete(clause.Table{Name: "order1", Alias: "o"}).From("user1", "u").
+		Where(clause.Expr{Sql: "o.user_id = u.id"}).Build(l, nil, 0)
+	assert.NoError(t, err)
+	assert.EqualValues(t, "DELETE `o` FROM `order1` AS `o`,`user1` AS `u` WHERE o.user_id = u.id", sql)
+
+	_, _, err = Delete("order1").From("user1").DeleteTargets("order1", "user1").Build(pg, nil, 0)
+	assert.True(t, errors.Is(err, ErrUnsupportedMultiTable))
+
+	sql, _, err = Delete("order1").Where(clause.Eq("Status", 1)).OrderBy("-Id").Limit(10).Build(l, nil, 0)
+	assert.NoError(t, err)
+	assert.EqualValues(t, "DELETE FROM `order1` WHERE `status` = ? ORDER BY `id` DESC LIMIT 10", sql)
+
+	_, _, err = Delete("order1").OrderBy("-Id").Build(lite, nil, 0)
+	assert.True(t, errors.Is(err, ErrUnsupportedMutationLimit))
+}
@@ -0,0 +1,54 @@
+package layer
+
+import (
+	"testing"
+
+	"github.com/meilihao/layer/clause"
+	"github.com/stretchr/testify/assert"
+)
+
+type quoteEvent struct {
+	Id   int `layer:";pk"`
+	Name string
+}
+
+func (quoteEvent) TableName() string {
+	return "events"
+}
+
+func (quoteEvent) TableSchema() string {
+	return "analytics"
+}
+
+func TestBuilder_Quote(t *testing.T) {
+	b := Select("e.Id", "analytics.events.UserName").From("analytics.events", "e").Where(clause.Eq("e.Id", 1))
+	sql, _, err := b.Build(l, nil, 0)
+	assert.NoError(t, err)
+	assert.EqualValues(t, "SELECT `e`.`id`,`analytics`.`events`.`user_name` FROM `analytics`.`events` AS `e` WHERE `e`.`id` = ?", sql)
+
+	// schema qualifier is not mapped
+	b = Select("Id").From(clause.Table{Schema: "OtherDB", Name: "UserInfo"}).
+		Join(clause.LeftJoin(clause.Table{Schema: "OtherDB", Name: "Role"}).On(clause.Expr{Sql: "1 = 1"}))
+	sql, _, err = b.Build(l, nil, 0)
+	assert.NoError(t, err)
+	assert.EqualValues(t, "SELECT `id` FROM `OtherDB`.`user_info` LEFT JOIN `OtherDB`.`role` ON 1 = 1", sql)
+
+	// quote characters are escaped
+	sql, _, err = Select("a`b").From("t`1").Build(l, nil, 0)
+	assert.NoError(t, err)
+	assert.EqualValues(t, "SELECT `a``b` FROM `t``1`", sql)
+
+	sql, _, err = Select(`a"b`, "e.*").From(&quoteEvent{}).Build(pg, nil, 0)
+	assert.NoError(t, err)
+	assert.EqualValues(t, `SELECT "a""b","e".* FROM "analytics"."events"`, sql)
+
+	se := l.NewFindSession().DryRun()
+	_, err = se.Find(&quoteEvent{Id: 1})
+	assert.NoError(t, err)
+	assert.EqualValues(t, "SELECT `id`,`name` FROM `analytics`.`events` WHERE `id` = ?", se.builder.String())
+
+	se = l.NewFindSession().DryRun().Table("archive.events")
+	_, err = se.Find(&quoteEvent{Id: 1})
+	assert.NoError(t, err)
+	assert.EqualValues(t, "SELECT `id`,`name` FROM `archive`.`events` WHERE `id` = ?", se.builder.String())
+}
@@ -30,7 +30,7 @@ func TestBuilder_Select(t *testing.T) {
 	).Where(clause.Eq("A", 1))
 	sql, args, err = b.Build(l, nil, 0)
 	assert.NoError(t, err)
-	assert.EqualValues(t, "SELECT `c`,`d` FROM `table1` LEFT JOIN `table2` ON `table1.id` = ? AND `table2.id` < ? RIGHT JOIN `table3` ON table2.id = table3.tid WHERE `a` = ?",
+	assert.EqualValues(t, "SELECT `c`,`d` FROM `table1` LEFT JOIN `table2` ON `table1`.`id` = ? AND `table2`.`id` < ? RIGHT JOIN `table3` ON table2.id = table3.tid WHERE `a` = ?",
 		sql)
 	assert.EqualValues(t, []interface{}{1, 3, 1}, args)
 
@@ -41,7 +41,7 @@ func TestBuilder_Select(t *testing.T) {
 	).Where(clause.Eq("A", 1))
 	sql, args, err = b.Build(l, nil, 0)
 	assert.NoError(t, err)
-	assert.EqualValues(t, "SELECT `c`,`d` FROM `table1` LEFT JOIN `table2` ON `table1.id` = ? AND `table2.id` < ? CROSS JOIN `table3` ON table2.id = table3.tid WHERE `a` = ?",
+	assert.EqualValues(t, "SELECT `c`,`d` FROM `table1` LEFT JOIN `table2` ON `table1`.`id` = ? AND `table2`.`id` < ? CROSS JOIN `table3` ON table2.id = table3.tid WHERE `a` = ?",
 		sql)
 	assert.EqualValues(t, []interface{}{1, 3, 1}, args)
 
@@ -52,7 +52,7 @@ func TestBuilder_Select(t *testing.T) {
 	).Where(clause.Eq("A", 1))
 	sql, args, err = b.Build(l, nil, 0)
 	assert.NoError(t, err)
-	assert.EqualValues(t, "SELECT `c`,`d` FROM `table1` LEFT JOIN `table2` ON `table1.id` = ? AND `table2.id` < ? FULL JOIN `table3` ON table2.id = table3.tid WHERE `a` = ?",
+	assert.EqualValues(t, "SELECT `c`,`d` FROM `table1` LEFT JOIN `table2` ON `table1`.`id` = ? AND `table2`.`id` < ? FULL JOIN `table3` ON table2.id = table3.tid WHERE `a` = ?",
 		sql)
 	assert.EqualValues(t, []interface{}{1, 3, 1}, args)
 
@@ -63,7 +63,7 @@ func TestBuilder_Select(t *testing.T) {
 	).Where(clause.Eq("A", 1))
 	sql, args, err = b.Build(l, nil, 0)
 	assert.NoError(t, err)
-	assert.EqualValues(t, "SELECT `c`,`d` FROM `table1` LEFT JOIN `table2` ON `table1.id` = ? AND `table2.id` < ? INNER JOIN `table3` ON table2.id = table3.tid WHERE `a` = ?",
+	assert.EqualValues(t, "SELECT `c`,`d` FROM `table1` LEFT JOIN `table2` ON `table1`.`id` = ? AND `table2`.`id` < ? INNER JOIN `table3` ON table2.id = table3.tid WHERE `a` = ?",
 		sql)
 	assert.EqualValues(t, []interface{}{1, 3, 1}, args)
 }
@@ -101,7 +101,7 @@ func TestBuilder_From(t *testing.T) {
 	b.Select("sub.id").From(Select("Id").From("table1").Where(clause.Eq("A", 1)), "sub").Where(clause.Eq("B", 1))
 	sql, args, err = b.Build(l, nil, 0)
 	assert.NoError(t, err)
-	assert.EqualValues(t, "SELECT `sub.id` FROM (SELECT `id` FROM `table1` WHERE `a` = ?) AS `sub` WHERE `b` = ?", sql)
+	assert.EqualValues(t, "SELECT `sub`.`id` FROM (SELECT `id` FROM `table1` WHERE `a` = ?) AS `sub` WHERE `b` = ?", sql)
 	assert.EqualValues(t, []interface{}{1, 1}, args)
 
 	// from sub without alias and with conditions
@@ -122,7 +122,7 @@ func TestBuilder_From(t *testing.T) {
 			Select("id").From("table1").Where(clause.Eq("a", 2))), "sub").Where(clause.Eq("b", 1))
 	sql, args, err = b.Build(l, nil, 0)
 	fmt.Println(sql, args, err)
-	assert.EqualValues(t, "SELECT `sub.id` FROM ((SELECT `id` FROM `table1` WHERE `a` = ?) UNION ALL (SELECT `id` FROM `table1` WHERE `a` = ?)) AS `sub` WHERE `b` = ?", sql)
+	assert.EqualValues(t, "SELECT `sub`.`id` FROM ((SELECT `id` FROM `table1` WHERE `a` = ?) UNION ALL (SELECT `id` FROM `table1` WHERE `a` = ?)) AS `sub` WHERE `b` = ?", sql)
 	assert.EqualValues(t, []interface{}{1, 2, 1}, args)
 
 	// from union without alias
@@ -2,6 +2,7 @@ package layer
 
 import (
 	"errors"
+	"fmt"
 	"sort"
 
 	"github.com/meilihao/layer/clause"
@@ -13,14 +14,27 @@ var (
 	ErrNoSupportedInput             = errors.New("not supported input")
 	ErrUnsupportedUnionMembers      = errors.New("Unexpected members in UNION query")
 	ErrNotUnexpectedUnionConditions = errors.New("Unexpected conditional fields in UNION query")
+	ErrRecursiveSQL                 = errors.New("sql refers to itself")
 )
 
+// compound a member of set operation
+type compound struct {
+	typ clause.UnionType
+	sql *SQL
+}
+
 type SQL struct {
 	err error
 	typ string
 	clause.Clauses
-	unionTpy clause.UnionType
-	unionSQL *SQL
+	compounds       []compound     // set operations, s itself is the first member
+	compoundClauses clause.Clauses // ORDER BY and LIMIT of the whole compound query
+	l               *Layer         // set by Bind
+	model           interface{}    // model passed to Select/From
+	frozen          bool           // see Freeze
+	models          []sqlModel     // models of From and Join
+	strict          bool           // see Strict
+	softDelete      bool           // see SoftDelete
 }
 
 func NewSQL() *SQL {
@@ -64,7 +78,10 @@ func InsertSelect(table interface{}, cols ...interface{}) *SQL {
 	return b
 }
 
+// Select columns, a model like &User{} expands to all its mapped columns
 func (s *SQL) Select(es ...interface{}) *SQL {
+	s = s.cow()
+
 	e := s.Clauses[clause.ClauseSelect]
 
 	var t *clause.Select
@@ -75,7 +92,18 @@ func (s *SQL) Select(es ...interface{}) *SQL {
 	}
 
 	if len(es) > 0 {
-		t.Columns = append(t.Columns, es...)
+		for _, v := range es {
+			switch v.(type) {
+			case string, clause.Column, clause.Expression:
+				t.Columns = append(t.Columns, v)
+			default:
+				if _, ok := s.modelTable(v); ok {
+					t.Columns = append(t.Columns, modelColumns{model: v})
+				} else {
+					t.Columns = append(t.Columns, v)
+				}
+			}
+		}
 	} else {
 		t.Columns = append(t.Columns, clause.Expr{Sql: "*"})
 	}
@@ -89,6 +117,8 @@ func (s *SQL) Select(es ...interface{}) *SQL {
 }
 
 func (s *SQL) Distinct(es ...interface{}) *SQL {
+	s = s.cow()
+
 	e := s.Clauses[clause.ClauseSelect]
 
 	var t *clause.Select
@@ -110,6 +140,8 @@ func (s *SQL) Distinct(es ...interface{}) *SQL {
 }
 
 func (s *SQL) Update(table interface{}) *SQL {
+	s = s.cow()
+
 	e := s.Clauses[clause.ClauseUpdate]
 
 	var t *clause.Update
@@ -133,6 +165,8 @@ func (s *SQL) Update(table interface{}) *SQL {
 }
 
 func (s *SQL) Set(es ...interface{}) *SQL {
+	s = s.cow()
+
 	e := s.Clauses[clause.ClauseSet]
 
 	var t *clause.Set
@@ -168,6 +202,8 @@ func (s *SQL) Set(es ...interface{}) *SQL {
 }
 
 func (s *SQL) Delete(table interface{}) *SQL {
+	s = s.cow()
+
 	e := s.Clauses[clause.ClauseDelete]
 
 	var t *clause.Delete
@@ -191,6 +227,8 @@ func (s *SQL) Delete(table interface{}) *SQL {
 }
 
 func (s *SQL) Insert(table interface{}) *SQL {
+	s = s.cow()
+
 	e := s.Clauses[clause.ClauseInsert]
 
 	var t *clause.Insert
@@ -214,6 +252,8 @@ func (s *SQL) Insert(table interface{}) *SQL {
 }
 
 func (s *SQL) InsertSelect(table interface{}, cols ...interface{}) *SQL {
+	s = s.cow()
+
 	e := s.Clauses[clause.ClauseInsertSelect]
 
 	var t *clause.InsertSelect
@@ -246,6 +286,8 @@ func (s *SQL) InsertSelect(table interface{}, cols ...interface{}) *SQL {
 }
 
 func (s *SQL) Values(es ...interface{}) *SQL {
+	s = s.cow()
+
 	e := s.Clauses[clause.ClauseValues]
 
 	var t *clause.Values
@@ -281,6 +323,8 @@ func (s *SQL) Values(es ...interface{}) *SQL {
 }
 
 func (s *SQL) From(table interface{}, alias ...string) *SQL {
+	s = s.cow()
+
 	e := s.Clauses[clause.ClauseFrom]
 
 	var t *clause.From
@@ -299,7 +343,18 @@ func (s *SQL) From(table interface{}, alias ...string) *SQL {
 	case *SQL:
 		tmp = clause.Table{SubQuery: v}
 	default:
-		panic(ErrNoSupportedInput)
+		var ok bool
+		if tmp, ok = s.modelTable(table); !ok {
+			s.err = fmt.Errorf("%w: from %T", ErrNoSupportedInput, table)
+
+			return s
+		}
+
+		if len(alias) > 0 {
+			s.addModel(table, tmp.Name, alias[0], -1)
+		} else {
+			s.addModel(table, tmp.Name, "", -1)
+		}
 	}
 
 	if len(alias) > 0 {
@@ -312,7 +367,26 @@ func (s *SQL) From(table interface{}, alias ...string) *SQL {
 	return s
 }
 
+// modelTable table of model v, ok is false if v is not a model.
+// v is parsed only when building, with the NameMapper of the Layer, since schema.SchemaCache keeps the first parse of a type.
+func (s *SQL) modelTable(v interface{}) (clause.Table, bool) {
+	t, err := schema.ModelType(v)
+	if err != nil {
+		return clause.Table{}, false
+	}
+
+	if s.model == nil {
+		s.model = v
+	}
+
+	name, tableSchema := schema.TableName(t)
+
+	return clause.Table{Schema: tableSchema, Name: name}, true
+}
+
 func (s *SQL) Join(joins ...interface{}) *SQL {
+	s = s.cow()
+
 	e := s.Clauses[clause.ClauseFrom]
 
 	var t *clause.From
@@ -331,7 +405,11 @@ func (s *SQL) Join(joins ...interface{}) *SQL {
 		case clause.Expression:
 			j = &clause.Join{Expression: tmp}
 		default:
-			panic(ErrNoSupportedInput)
+			// model, ON is derived from many2one
+			if s = s.JoinModel(clause.JoinInner, tmp); s.err != nil {
+				return s
+			}
+			continue
 		}
 
 		t.Joins = append(t.Joins, *j)
@@ -342,22 +420,51 @@ func (s *SQL) Join(joins ...interface{}) *SQL {
 	return s
 }
 
-func (s *SQL) union(typ clause.UnionType, sub *SQL) *SQL {
-	if s.Clauses[clause.ClauseLimit] != nil || s.Clauses[clause.ClauseOrderBy] != nil ||
-		s.Clauses[clause.ClauseGroupBy] != nil {
-		s.err = ErrNotUnexpectedUnionConditions
+func (s *SQL) with(name string, sub *SQL, recursive bool, cols []string) *SQL {
+	s = s.cow()
 
-		return s
+	e := s.Clauses[clause.ClauseWith]
+
+	var t *clause.With
+	if e != nil {
+		t = e.(*clause.With)
+	} else {
+		t = &clause.With{}
 	}
 
-	last := s
+	t.CTEs = append(t.CTEs, clause.CTE{
+		Name:      name,
+		Columns:   cols,
+		Recursive: recursive,
+		Query:     sub,
+	})
+
+	s.Clauses[clause.ClauseWith] = t
 
-	for last.unionSQL != nil {
-		last = last.unionSQL
+	return s
+}
+
+// With add a common table expression, use name in From or Join to refer to it
+func (s *SQL) With(name string, sub *SQL, cols ...string) *SQL {
+	return s.with(name, sub, false, cols)
+}
+
+// WithRecursive add a recursive common table expression, sub is usually a UNION ALL which refers to name
+func (s *SQL) WithRecursive(name string, sub *SQL, cols ...string) *SQL {
+	return s.with(name, sub, true, cols)
+}
+
+func (s *SQL) union(typ clause.UnionType, sub *SQL) *SQL {
+	s = s.cow()
+
+	if len(s.compounds) == 0 && (s.Clauses[clause.ClauseLimit] != nil || s.Clauses[clause.ClauseOrderBy] != nil ||
+		s.Clauses[clause.ClauseGroupBy] != nil) {
+		s.err = ErrNotUnexpectedUnionConditions
+
+		return s
 	}
 
-	sub.unionTpy = typ
-	last.unionSQL = sub
+	s.compounds = append(s.compounds, compound{typ: typ, sql: sub})
 
 	return s
 }
@@ -374,7 +481,38 @@ func (s *SQL) UnionDistinct(sub *SQL) *SQL {
 	return s.union(clause.UnionDistinct, sub)
 }
 
+func (s *SQL) Intersect(sub *SQL) *SQL {
+	return s.union(clause.Intersect, sub)
+}
+
+func (s *SQL) IntersectAll(sub *SQL) *SQL {
+	return s.union(clause.IntersectAll, sub)
+}
+
+func (s *SQL) Except(sub *SQL) *SQL {
+	return s.union(clause.Except, sub)
+}
+
+func (s *SQL) ExceptAll(sub *SQL) *SQL {
+	return s.union(clause.ExceptAll, sub)
+}
+
+// tail clauses of s, it is the clauses of the whole compound query after Union(), Intersect() or Except()
+func (s *SQL) tail() clause.Clauses {
+	if len(s.compounds) == 0 {
+		return s.Clauses
+	}
+
+	if s.compoundClauses == nil {
+		s.compoundClauses = make(clause.Clauses, 2)
+	}
+
+	return s.compoundClauses
+}
+
 func (s *SQL) Where(es ...clause.Expression) *SQL {
+	s = s.cow()
+
 	e := s.Clauses[clause.ClauseWhere]
 
 	var t *clause.Where
@@ -391,8 +529,12 @@ func (s *SQL) Where(es ...clause.Expression) *SQL {
 	return s
 }
 
+// OrderBy it orders the whole compound query after Union(), Intersect() or Except()
 func (s *SQL) OrderBy(es ...interface{}) *SQL {
-	e := s.Clauses[clause.ClauseOrderBy]
+	s = s.cow()
+
+	cs := s.tail()
+	e := cs[clause.ClauseOrderBy]
 
 	var t *clause.OrderBy
 	if e != nil {
@@ -405,13 +547,17 @@ func (s *SQL) OrderBy(es ...interface{}) *SQL {
 		}
 	}
 
-	s.Clauses[clause.ClauseOrderBy] = t
+	cs[clause.ClauseOrderBy] = t
 
 	return s
 }
 
+// Limit Limit(limit) or Limit(limit, offset), it limits the whole compound query after Union(), Intersect() or Except()
 func (s *SQL) Limit(n ...int) *SQL {
-	e := s.Clauses[clause.ClauseLimit]
+	s = s.cow()
+
+	cs := s.tail()
+	e := cs[clause.ClauseLimit]
 
 	var t *clause.Limit
 	if e != nil {
@@ -425,16 +571,19 @@ func (s *SQL) Limit(n ...int) *SQL {
 		t.Limit = n[0]
 	case 2:
 		t.Limit = n[0]
-		t.Limit = n[1]
+		t.Offset = n[1]
 	}
 
-	s.Clauses[clause.ClauseLimit] = t
+	cs[clause.ClauseLimit] = t
 
 	return s
 }
 
 func (s *SQL) Offset(n int) *SQL {
-	e := s.Clauses[clause.ClauseLimit]
+	s = s.cow()
+
+	cs := s.tail()
+	e := cs[clause.ClauseLimit]
 
 	var t *clause.Limit
 	if e != nil {
@@ -447,12 +596,14 @@ func (s *SQL) Offset(n int) *SQL {
 		}
 	}
 
-	s.Clauses[clause.ClauseLimit] = t
+	cs[clause.ClauseLimit] = t
 
 	return s
 }
 
 func (s *SQL) GroupBy(es ...interface{}) *SQL {
+	s = s.cow()
+
 	e := s.Clauses[clause.ClauseGroupBy]
 
 	var t *clause.GroupBy
@@ -477,6 +628,8 @@ func (s *SQL) GroupBy(es ...interface{}) *SQL {
 }
 
 func (s *SQL) Having(es ...clause.Expression) *SQL {
+	s = s.cow()
+
 	e := s.Clauses[clause.ClauseGroupBy]
 
 	var t *clause.GroupBy
@@ -493,60 +646,198 @@ func (s *SQL) Having(es ...clause.Expression) *SQL {
 	return s
 }
 
+// Lock set row locking clause, like clause.ForUpdate().Of("t1").SkipLocked()
+func (s *SQL) Lock(locking clause.Locking) *SQL {
+	s = s.cow()
+
+	s.Clauses[clause.ClauseLocking] = locking
+
+	return s
+}
+
+// ForUpdate SELECT ... FOR UPDATE [NOWAIT|SKIP LOCKED]
+func (s *SQL) ForUpdate(option ...string) *SQL {
+	return s.Lock(newLocking(clause.LockUpdate, option))
+}
+
+// ForShare SELECT ... FOR SHARE [NOWAIT|SKIP LOCKED]
+func (s *SQL) ForShare(option ...string) *SQL {
+	return s.Lock(newLocking(clause.LockShare, option))
+}
+
+func newLocking(strength string, option []string) clause.Locking {
+	t := clause.Locking{Strength: strength}
+	if len(option) > 0 {
+		t.Option = option[0]
+	}
+
+	return t
+}
+
+// Window define a named window, use it by clause.WindowFunc.OverName(name) or as Window.Base
+func (s *SQL) Window(name string, w clause.Window) *SQL {
+	s = s.cow()
+
+	e := s.Clauses[clause.ClauseWindow]
+
+	var t *clause.Windows
+	if e != nil {
+		t = e.(*clause.Windows)
+	} else {
+		t = &clause.Windows{}
+	}
+
+	t.Windows = append(t.Windows, clause.NamedWindow{Name: name, Window: w})
+
+	s.Clauses[clause.ClauseWindow] = t
+
+	return s
+}
+
 func (s *SQL) Build(l *Layer, schema *schema.Schema, initGrow int) (sql string, args []interface{}, err error) {
+	builder := NewSQLBuilder(l, schema, initGrow)
+	if err = s.build(builder); err != nil {
+		return "", nil, err
+	}
+
+	if err = builder.Err(); err != nil {
+		return "", nil, err
+	}
+
+	return builder.String(), builder.Args, nil
+}
+
+// build write s into builder, sub queries share the builder with the main one
+func (s *SQL) build(builder *SQLBuilder) (err error) {
+	if builder.dupSQL[s] {
+		return ErrRecursiveSQL
+	}
+	builder.dupSQL[s] = true
+	defer delete(builder.dupSQL, s)
+
 	if s.err != nil {
-		return "", nil, s.err
+		return s.err
 	}
 
-	builder := NewSQLBuilder(l, schema, initGrow)
-	for s != nil {
-		if s.unionSQL != nil && s.unionSQL.IsUnion() && s.typ != clause.ClauseSelect {
-			return "", nil, ErrUnsupportedUnionMembers
+	if len(s.compounds) == 0 {
+		return s.buildSingle(builder)
+	}
+
+	// split members into terms by UNION and EXCEPT, a term of INTERSECT members is parenthesized
+	// so the precedence is the same in all dialects
+	members := make([]compound, 0, len(s.compounds)+1)
+	members = append(members, compound{sql: s})
+	members = append(members, s.compounds...)
+
+	d := builder.Dialect()
+	nested := d.HasNestedCompound()
+	for _, m := range members[1:] {
+		if err = d.Compound(string(m.typ)); err != nil {
+			return err
 		}
+	}
 
-		if s.unionTpy != clause.UnionNil {
-			builder.WriteString(" " + string(s.unionTpy) + " ")
+	for i := 0; i < len(members); {
+		j := i + 1
+		for j < len(members) && members[j].typ.IsIntersect() {
+			j++
 		}
-		if s.unionSQL != nil || s.unionTpy != clause.UnionNil {
-			builder.WriteByte('(')
+
+		wrap := j-i > 1 && j-i < len(members)
+		if !nested {
+			// left-associative without parentheses, only the first term can have INTERSECT
+			if wrap && i > 0 {
+				return fmt.Errorf("%w: INTERSECT after UNION or EXCEPT in %s", ErrUnsupportedCompound, d.Dialect())
+			}
+			wrap = false
 		}
 
-		switch s.typ {
-		case clause.ClauseInsert:
-			err = s.Clauses.Build(builder, clause.ClauseInsert, clause.ClauseValues)
-		case clause.ClauseUpdate:
-			err = s.Clauses.Build(builder, clause.ClauseUpdate, clause.ClauseSet, clause.ClauseWhere)
-		case clause.ClauseDelete:
-			err = s.Clauses.Build(builder, clause.ClauseDelete, clause.ClauseWhere)
-		case clause.ClauseSelect, clause.ClauseInsertSelect:
-			err = s.Clauses.Build(builder, clause.ClauseInsertSelect, clause.ClauseSelect, clause.ClauseFrom,
-				clause.ClauseWhere, clause.ClauseGroupBy, clause.ClauseOrderBy, clause.ClauseLimit)
-		default:
-			err = ErrSQLBuildTarget
+		if i > 0 {
+			builder.WriteString(" " + string(members[i].typ) + " ")
+		}
+		if wrap {
+			builder.WriteByte('(')
+		}
+		for k := i; k < j; k++ {
+			if k > i {
+				builder.WriteString(" " + string(members[k].typ) + " ")
+			}
+			if err = s.buildMember(builder, members[k].sql, nested); err != nil {
+				return err
+			}
+		}
+		if wrap {
+			builder.WriteByte(')')
 		}
 
-		if err != nil {
-			return "", nil, err
+		i = j
+	}
+
+	return s.compoundClauses.Build(builder, clause.ClauseOrderBy, clause.ClauseLimit)
+}
+
+func (s *SQL) buildMember(builder *SQLBuilder, m *SQL, nested bool) (err error) {
+	if m.typ != clause.ClauseSelect {
+		return ErrUnsupportedUnionMembers
+	}
+
+	if !nested {
+		// a member which needs parentheses
+		if m != s && (len(m.compounds) > 0 || m.Clauses[clause.ClauseWith] != nil ||
+			m.Clauses[clause.ClauseOrderBy] != nil || m.Clauses[clause.ClauseLimit] != nil) {
+			return fmt.Errorf("%w: member with set operation, WITH, ORDER BY or LIMIT in %s", ErrUnsupportedCompound, builder.Dialect().Dialect())
 		}
 
-		if s.unionSQL != nil || s.unionTpy != clause.UnionNil {
-			builder.WriteByte(')')
+		if m == s {
+			return s.buildSingle(builder)
 		}
 
-		sql = builder.String()
-		args = builder.Args
+		return m.build(builder)
+	}
 
-		s = s.unionSQL
+	builder.WriteByte('(')
+	if m == s {
+		err = s.buildSingle(builder)
+	} else {
+		err = m.build(builder)
 	}
+	builder.WriteByte(')')
 
-	return
+	return err
 }
 
-func (s *SQL) IsUnion() bool {
-	switch s.unionTpy {
-	case clause.Union, clause.UnionAll, clause.UnionDistinct:
-		return true
+func (s *SQL) buildSingle(builder *SQLBuilder) error {
+	if s.strict || builder.models != nil {
+		restore, err := builder.useModels(s)
+		if err != nil {
+			return err
+		}
+		defer restore()
+	}
+
+	switch s.typ {
+	case clause.ClauseInsert:
+		return s.Clauses.Build(builder, clause.ClauseWith, clause.ClauseInsert, clause.ClauseValues)
+	case clause.ClauseUpdate, clause.ClauseDelete:
+		return s.buildMutation(builder)
+	case clause.ClauseSelect, clause.ClauseInsertSelect:
+		// WITH belongs to the SELECT part of INSERT ... SELECT, which is supported by all dialects
+		cs := s.Clauses
+		if s.softDelete {
+			var err error
+			if cs, err = s.softDeleteClauses(builder); err != nil {
+				return err
+			}
+		}
+
+		return cs.Build(builder, clause.ClauseInsertSelect, clause.ClauseWith, clause.ClauseSelect, clause.ClauseFrom,
+			clause.ClauseWhere, clause.ClauseGroupBy, clause.ClauseWindow, clause.ClauseOrderBy, clause.ClauseLimit, clause.ClauseLocking)
 	}
 
-	return false
+	return ErrSQLBuildTarget
+}
+
+// IsUnion whether s is a compound query
+func (s *SQL) IsUnion() bool {
+	return len(s.compounds) > 0
 }
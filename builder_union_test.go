@@ -1,6 +1,8 @@
 package layer
 
 import (
+	"context"
+	"errors"
 	"testing"
 
 	"github.com/meilihao/layer/clause"
@@ -40,3 +42,86 @@ func TestBuilder_Union(t *testing.T) {
 	assert.Error(t, err)
 	assert.EqualValues(t, ErrUnsupportedUnionMembers, err)
 }
+
+func TestBuilder_Compound(t *testing.T) {
+	// INTERSECT binds more tightly
+	b := Select("Id").From("t1").
+		Union(Select("Id").From("t2")).
+		Intersect(Select("Id").From("t3").Where(clause.Eq("A", 1))).
+		Except(Select("Id").From("t4"))
+	sql, args, err := b.Build(l, nil, 0)
+	assert.NoError(t, err)
+	assert.EqualValues(t, "(SELECT `id` FROM `t1`) UNION ((SELECT `id` FROM `t2`) INTERSECT (SELECT `id` FROM `t3` WHERE `a` = ?)) EXCEPT (SELECT `id` FROM `t4`)", sql)
+	assert.EqualValues(t, []interface{}{1}, args)
+
+	b = Select("Id").From("t1").IntersectAll(Select("Id").From("t2")).ExceptAll(Select("Id").From("t3"))
+	sql, _, err = b.Build(l, nil, 0)
+	assert.NoError(t, err)
+	assert.EqualValues(t, "((SELECT `id` FROM `t1`) INTERSECT ALL (SELECT `id` FROM `t2`)) EXCEPT ALL (SELECT `id` FROM `t3`)", sql)
+
+	// nested compound is a member
+	b = Select("Id").From("t1").Intersect(Select("Id").From("t2").Union(Select("Id").From("t3")))
+	sql, _, err = b.Build(l, nil, 0)
+	assert.NoError(t, err)
+	assert.EqualValues(t, "(SELECT `id` FROM `t1`) INTERSECT ((SELECT `id` FROM `t2`) UNION (SELECT `id` FROM `t3`))", sql)
+
+	// ORDER BY and LIMIT after union belong to the whole query
+	b = Select("Id").From("t1").Where(clause.Eq("A", 1)).
+		UnionAll(Select("Id").From("t2").OrderBy("Id").Limit(3)).
+		OrderBy("-Id").Limit(10, 20)
+	sql, args, err = b.Build(l, nil, 0)
+	assert.NoError(t, err)
+	assert.EqualValues(t, "(SELECT `id` FROM `t1` WHERE `a` = ?) UNION ALL (SELECT `id` FROM `t2` ORDER BY `id` ASC LIMIT 3) ORDER BY `id` DESC LIMIT 10 OFFSET 20", sql)
+	assert.EqualValues(t, []interface{}{1}, args)
+
+	b = Select("sub.Id").From(b, "sub").Where(clause.Gt("sub.Id", 5))
+	sql, args, err = b.Build(l, nil, 0)
+	assert.NoError(t, err)
+	assert.EqualValues(t, "SELECT `sub`.`id` FROM ((SELECT `id` FROM `t1` WHERE `a` = ?) UNION ALL (SELECT `id` FROM `t2` ORDER BY `id` ASC LIMIT 3) ORDER BY `id` DESC LIMIT 10 OFFSET 20) AS `sub` WHERE `sub`.`id` > ?", sql)
+	assert.EqualValues(t, []interface{}{1, 5}, args)
+
+	b = Select("Id").From("t1").Except(Update("t2").Set(map[string]interface{}{"A": 1}))
+	_, _, err = b.Build(l, nil, 0)
+	assert.EqualValues(t, ErrUnsupportedUnionMembers, err)
+}
+
+func TestBuilder_CompoundSQLite(t *testing.T) {
+	sl := newSQLite(t)
+	ctx := context.Background()
+
+	for _, stmt := range []string{
+		"CREATE TABLE t1 (id INTEGER)", "CREATE TABLE t2 (id INTEGER)", "CREATE TABLE t3 (id INTEGER)",
+		"INSERT INTO t1 VALUES (1),(2),(3)", "INSERT INTO t2 VALUES (2),(3)", "INSERT INTO t3 VALUES (3),(4)",
+	} {
+		_, err := sl.Exec(stmt)
+		assert.NoError(t, err)
+	}
+
+	// members are not parenthesized, INTERSECT is allowed in the first term only
+	b := Select("Id").From("t1").Intersect(Select("Id").From("t2")).Union(Select("Id").From("t3")).OrderBy("Id")
+	sql, _, err := b.Build(sl, nil, 0)
+	assert.NoError(t, err)
+	assert.EqualValues(t, `SELECT "id" FROM "t1" INTERSECT SELECT "id" FROM "t2" UNION SELECT "id" FROM "t3" ORDER BY "id" ASC`, sql)
+
+	var ids []int
+	assert.NoError(t, b.Bind(sl).All(ctx, &ids))
+	assert.EqualValues(t, []int{2, 3, 4}, ids)
+
+	_, _, err = Select("Id").From("t1").Union(Select("Id").From("t2")).Intersect(Select("Id").From("t3")).Build(sl, nil, 0)
+	assert.True(t, errors.Is(err, ErrUnsupportedCompound))
+
+	_, _, err = Select("Id").From("t1").Intersect(Select("Id").From("t2").Union(Select("Id").From("t3"))).Build(sl, nil, 0)
+	assert.True(t, errors.Is(err, ErrUnsupportedCompound))
+
+	_, _, err = Select("Id").From("t1").UnionAll(Select("Id").From("t2").Limit(1)).Build(sl, nil, 0)
+	assert.True(t, errors.Is(err, ErrUnsupportedCompound))
+
+	for _, b := range []*SQL{
+		Select("Id").From("t1").IntersectAll(Select("Id").From("t2")),
+		Select("Id").From("t1").ExceptAll(Select("Id").From("t2")),
+		Select("Id").From("t1").UnionDistinct(Select("Id").From("t2")),
+	} {
+		_, _, err = b.Build(sl, nil, 0)
+		assert.True(t, errors.Is(err, ErrUnsupportedCompound))
+	}
+}
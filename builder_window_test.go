@@ -0,0 +1,55 @@
+package layer
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/meilihao/layer/clause"
+	"github.com/meilihao/layer/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuilder_Window(t *testing.T) {
+	b := Select("Id", clause.RowNumber().Over(clause.NewWindow().Partition("UserId").Order("-CreatedAt")).As("Rn")).
+		From("order1")
+	sql, args, err := b.Build(l, nil, 0)
+	assert.NoError(t, err)
+	assert.EqualValues(t, "SELECT `id`,ROW_NUMBER() OVER (PARTITION BY `user_id` ORDER BY `created_at` DESC) AS `rn` FROM `order1`", sql)
+	assert.EqualValues(t, 0, len(args))
+
+	// frame
+	b = Select("Id", clause.Sum("Amount").Over(clause.NewWindow().Order("Id").Rows(clause.UnboundedPreceding(), clause.CurrentRow())).As("Total")).
+		From("order1")
+	sql, _, err = b.Build(l, nil, 0)
+	assert.NoError(t, err)
+	assert.EqualValues(t, "SELECT `id`,SUM(`amount`) OVER (ORDER BY `id` ASC ROWS BETWEEN UNBOUNDED PRECEDING AND CURRENT ROW) AS `total` FROM `order1`", sql)
+
+	b = Select(clause.Avg("Amount").Over(clause.NewWindow().Order("Id").Range(clause.Preceding(3), clause.Following(clause.Expr{Sql: "INTERVAL 1 DAY"})))).
+		From("order1")
+	sql, _, err = b.Build(l, nil, 0)
+	assert.NoError(t, err)
+	assert.EqualValues(t, "SELECT AVG(`amount`) OVER (ORDER BY `id` ASC RANGE BETWEEN 3 PRECEDING AND INTERVAL 1 DAY FOLLOWING) FROM `order1`", sql)
+
+	// named window, args of function and frame are in order
+	b = Select(clause.Lag("Amount", 1, 0).OverName("w"), clause.Count(nil).Over(clause.Window{Base: "w", Frame: &clause.Frame{Type: clause.FrameGroups, Start: clause.Preceding(int64(2))}})).
+		From("order1").Where(clause.Gt("Amount", 100)).Window("w", clause.NewWindow().Partition("UserId").Order("Id")).
+		OrderBy(clause.RowNumber().OverName("w"))
+	sql, args, err = b.Build(pg, nil, 0)
+	assert.NoError(t, err)
+	assert.EqualValues(t, `SELECT LAG("amount",$1,$2) OVER "w",COUNT(*) OVER ("w" GROUPS $3 PRECEDING) FROM "order1" WHERE "amount" > $4 WINDOW "w" AS (PARTITION BY "user_id" ORDER BY "id" ASC) ORDER BY ROW_NUMBER() OVER "w"`, sql)
+	assert.EqualValues(t, []interface{}{1, 0, int64(2), 100}, args)
+
+	// mysql has no GROUPS frame
+	_, _, err = b.Build(l, nil, 0)
+	assert.True(t, errors.Is(err, ErrUnsupportedFrame))
+
+	b = Select(clause.Ntile(4).Over(clause.NewWindow().Order("Id"))).From("order1").Window("", clause.NewWindow())
+	_, _, err = b.Build(l, nil, 0)
+	assert.Equal(t, clause.ErrEmptyWindowName, err)
+
+	// columns are validated against schema
+	s, err := schema.Parse(&errUser{}, l.opts.nameMapper)
+	assert.NoError(t, err)
+	_, _, err = Select(clause.RowNumber().Over(clause.NewWindow().Partition("Age"))).From("err_user").Build(l, s, 0)
+	assert.True(t, errors.Is(err, ErrNoColumn))
+}
@@ -0,0 +1,65 @@
+package layer
+
+import (
+	"testing"
+
+	"github.com/meilihao/layer/clause"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuilder_With(t *testing.T) {
+	sub := Select("Id").From("table1").Where(clause.Eq("Status", 1))
+
+	b := Select("Id").From("t1").With("t1", sub).Where(clause.Gt("Id", 10))
+	sql, args, err := b.Build(l, nil, 0)
+	assert.NoError(t, err)
+	assert.EqualValues(t, "WITH `t1` AS (SELECT `id` FROM `table1` WHERE `status` = ?) SELECT `id` FROM `t1` WHERE `id` > ?", sql)
+	assert.EqualValues(t, []interface{}{1, 10}, args)
+
+	// cte in join
+	b = Select("Id").From("table2").With("t1", sub, "Id").Join(clause.InnerJoin("t1").On(clause.Expr{Sql: "t1.id = table2.id"}))
+	sql, _, err = b.Build(l, nil, 0)
+	assert.NoError(t, err)
+	assert.EqualValues(t, "WITH `t1` (`id`) AS (SELECT `id` FROM `table1` WHERE `status` = ?) SELECT `id` FROM `table2` INNER JOIN `t1` ON t1.id = table2.id", sql)
+
+	// recursive
+	tree := Select("Id", "Pid").From("node").Where(clause.Eq("Id", 1)).
+		UnionAll(Select(clause.Expr{Sql: "node.id,node.pid"}).From("node").Join(clause.InnerJoin("tree").On(clause.Expr{Sql: "node.pid = tree.id"})))
+	b = Select("Id").From("tree").WithRecursive("tree", tree, "Id", "Pid")
+	sql, args, err = b.Build(l, nil, 0)
+	assert.NoError(t, err)
+	assert.EqualValues(t, "WITH RECURSIVE `tree` (`id`,`pid`) AS ((SELECT `id`,`pid` FROM `node` WHERE `id` = ?) UNION ALL (SELECT node.id,node.pid FROM `node` INNER JOIN `tree` ON node.pid = tree.id)) SELECT `id` FROM `tree`", sql)
+	assert.EqualValues(t, []interface{}{1}, args)
+
+	b = Delete("table1").With("t1", sub).Where(clause.Expr{Sql: "id IN (?)", Args: []interface{}{Select("Id").From("t1")}})
+	sql, args, err = b.Build(l, nil, 0)
+	assert.NoError(t, err)
+	assert.EqualValues(t, "WITH `t1` AS (SELECT `id` FROM `table1` WHERE `status` = ?) DELETE FROM `table1` WHERE id IN (SELECT `id` FROM `t1`)", sql)
+	assert.EqualValues(t, []interface{}{1}, args)
+
+	b = Update("table1").With("t1", sub).Set(map[string]interface{}{"A": 2}).Where(clause.Eq("B", 3))
+	sql, args, err = b.Build(l, nil, 0)
+	assert.NoError(t, err)
+	assert.EqualValues(t, "WITH `t1` AS (SELECT `id` FROM `table1` WHERE `status` = ?) UPDATE `table1` SET `a`=? WHERE `b` = ?", sql)
+	assert.EqualValues(t, []interface{}{1, 2, 3}, args)
+
+	b = InsertSelect("table2", "Id").With("t1", sub).Select("Id").From("t1")
+	sql, _, err = b.Build(l, nil, 0)
+	assert.NoError(t, err)
+	assert.EqualValues(t, "INSERT INTO `table2` (`id`) WITH `t1` AS (SELECT `id` FROM `table1` WHERE `status` = ?) SELECT `id` FROM `t1`", sql)
+
+	// placeholders are numbered across sub queries
+	b = Select("Id").From("t1").With("t1", sub).Where(clause.Gt("Id", 10), clause.Expr{Sql: "a BETWEEN ? AND ?", Args: []interface{}{2, 3}})
+	sql, args, err = b.Build(pg, nil, 0)
+	assert.NoError(t, err)
+	assert.EqualValues(t, `WITH "t1" AS (SELECT "id" FROM "table1" WHERE "status" = $1) SELECT "id" FROM "t1" WHERE "id" > $2 AND (a BETWEEN $3 AND $4)`, sql)
+	assert.EqualValues(t, []interface{}{1, 10, 2, 3}, args)
+
+	_, _, err = Select("Id").From("t1").With("", sub).Build(l, nil, 0)
+	assert.Equal(t, clause.ErrEmptyCTE, err)
+
+	b = Select("Id").From("t1")
+	b.With("t1", b)
+	_, _, err = b.Build(l, nil, 0)
+	assert.Equal(t, ErrRecursiveSQL, err)
+}
@@ -0,0 +1,107 @@
+package clause
+
+import "errors"
+
+var (
+	ErrEmptyCase = errors.New("CASE needs at least one WHEN")
+)
+
+// When a WHEN ... THEN ... branch of CASE
+type When struct {
+	Cond interface{} // Expression for searched CASE, value for simple CASE
+	Then interface{}
+}
+
+// CaseExpr CASE expression. Value, the values of When and Then and Default are args unless they are Column or Expression,
+// Value is a column if it is a string.
+type CaseExpr struct {
+	Value   interface{} // simple CASE if it is not nil
+	Whens   []When
+	Default interface{} // ELSE
+	Alias   string
+}
+
+// Case searched CASE without value, simple CASE with value, like Case("Status")
+func Case(value ...interface{}) CaseExpr {
+	c := CaseExpr{}
+	if len(value) > 0 {
+		c.Value = value[0]
+	}
+
+	return c
+}
+
+func (c CaseExpr) When(cond, then interface{}) CaseExpr {
+	c.Whens = append(c.Whens[:len(c.Whens):len(c.Whens)], When{Cond: cond, Then: then})
+
+	return c
+}
+
+func (c CaseExpr) Else(v interface{}) CaseExpr {
+	c.Default = v
+
+	return c
+}
+
+func (c CaseExpr) As(alias string) CaseExpr {
+	c.Alias = alias
+
+	return c
+}
+
+func (c CaseExpr) Build(builder Builder) error {
+	if len(c.Whens) == 0 {
+		return ErrEmptyCase
+	}
+
+	builder.WriteString("CASE")
+
+	if c.Value != nil {
+		builder.WriteByte(' ')
+		if err := buildOperand(builder, c.Value); err != nil {
+			return err
+		}
+	}
+
+	for _, w := range c.Whens {
+		builder.WriteString(" WHEN ")
+		if err := buildValue(builder, w.Cond); err != nil {
+			return err
+		}
+
+		builder.WriteString(" THEN ")
+		if err := buildValue(builder, w.Then); err != nil {
+			return err
+		}
+	}
+
+	if c.Default != nil {
+		builder.WriteString(" ELSE ")
+		if err := buildValue(builder, c.Default); err != nil {
+			return err
+		}
+	}
+
+	builder.WriteString(" END")
+
+	if c.Alias != "" {
+		builder.WriteString(" AS ")
+		builder.WriteQuoted(c.Alias)
+	}
+
+	return nil
+}
+
+// buildValue Column and Expression are built, others are args
+func buildValue(builder Builder, v interface{}) error {
+	switch v := v.(type) {
+	case Column:
+		builder.WriteQuoted(v)
+	case Expression:
+		return v.Build(builder)
+	default:
+		builder.AppendArg(v)
+	}
+
+	return nil
+}
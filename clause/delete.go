@@ -6,10 +6,22 @@ var (
 
 type Delete struct {
 	Table
+	Targets []Table // tables whose rows are deleted by mysql multi-table DELETE, Table is the first one of FROM
 }
 
 func (e Delete) Build(builder Builder) error {
-	builder.WriteString("DELETE FROM ")
+	builder.WriteString("DELETE ")
+	for idx, t := range e.Targets {
+		if idx > 0 {
+			builder.WriteByte(',')
+		}
+		builder.WriteQuoted(t)
+	}
+	if len(e.Targets) > 0 {
+		builder.WriteByte(' ')
+	}
+
+	builder.WriteString("FROM ")
 	builder.WriteQuoted(e.Table)
 
 	return nil
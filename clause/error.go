@@ -0,0 +1,98 @@
+package clause
+
+import (
+	"errors"
+	"strings"
+)
+
+var (
+	ErrUnsupportedColumn = errors.New("unsupported column type")
+)
+
+// BuildError an invalid column or clause found while building sql
+type BuildError struct {
+	Clause string // clause name, like WHERE
+	Column string
+	Err    error // why it is invalid
+}
+
+func (e *BuildError) Error() string {
+	var b strings.Builder
+
+	b.WriteString("layer : build")
+	if e.Clause != "" {
+		b.WriteString(" clause ")
+		b.WriteString(e.Clause)
+	}
+	if e.Column != "" {
+		b.WriteString(" column ")
+		b.WriteString(e.Column)
+	}
+	b.WriteString(": ")
+	b.WriteString(e.Err.Error())
+
+	return b.String()
+}
+
+func (e *BuildError) Unwrap() error {
+	return e.Err
+}
+
+// Errors errors accumulated by Builder
+type Errors []error
+
+func (es Errors) Error() string {
+	ss := make([]string, 0, len(es))
+	for _, e := range es {
+		ss = append(ss, e.Error())
+	}
+
+	return strings.Join(ss, "; ")
+}
+
+// Unwrap returns the first error
+func (es Errors) Unwrap() error {
+	if len(es) == 0 {
+		return nil
+	}
+
+	return es[0]
+}
+
+// As check every error
+func (es Errors) As(target interface{}) bool {
+	for _, e := range es {
+		if errors.As(e, target) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Is check every error
+func (es Errors) Is(target error) bool {
+	for _, e := range es {
+		if errors.Is(e, target) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// withClause set clause name for BuildError which has not one
+func withClause(err error, name string) error {
+	switch v := err.(type) {
+	case *BuildError:
+		if v.Clause == "" {
+			v.Clause = name
+		}
+	case Errors:
+		for _, e := range v {
+			withClause(e, name)
+		}
+	}
+
+	return err
+}
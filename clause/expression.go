@@ -4,6 +4,8 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+
+	"github.com/meilihao/layer/dialect"
 )
 
 var (
@@ -16,7 +18,9 @@ type Column struct {
 	Alias string
 }
 
+// Table Name may be qualified like "schema.table" if Schema is empty
 type Table struct {
+	Schema   string // schema(postgres) or database(mysql), written as is
 	Name     string
 	Alias    string
 	SubQuery interface{}
@@ -32,6 +36,10 @@ func (m Clauses) Build(builer Builder, clauses ...string) error {
 			if err = c.Build(builer); err != nil {
 				return err
 			}
+
+			if err = builer.Err(); err != nil {
+				return withClause(err, name)
+			}
 		}
 	}
 
@@ -48,6 +56,11 @@ type Builder interface {
 	Writer
 	WriteQuoted(filed interface{})
 	AppendArg(...interface{})
+	// AddError record an invalid column or clause, it is returned by Clauses.Build
+	AddError(error)
+	Err() error
+	// Dialect dialect of the sql being built
+	Dialect() dialect.Dialecter
 }
 
 // Expression expression interface
@@ -61,16 +74,17 @@ type Expr struct {
 	Args []interface{}
 }
 
+// Build replace every '?' in Sql with the placeholder of the arg
 func (e Expr) Build(builder Builder) error {
-	if _, err := builder.WriteString(e.Sql); err != nil {
-		return err
-	}
-
 	if len(e.Args) > 0 {
-		builder.AppendArg(e.Args...)
+		builder.AppendArg(e)
+
+		return nil
 	}
 
-	return nil
+	_, err := builder.WriteString(e.Sql)
+
+	return err
 }
 
 func generateColumn(col interface{}) Column {
@@ -85,10 +99,8 @@ func generateColumn(col interface{}) Column {
 }
 
 func buildColumn(col string) Column {
-	if strings.Contains(col, ".") {
-		ns := strings.SplitN(col, ".", 2)
-
-		return Column{Table: ns[0], Name: ns[1]}
+	if idx := strings.LastIndexByte(col, '.'); idx > 0 {
+		return Column{Table: col[:idx], Name: col[idx+1:]}
 	}
 
 	return Column{Name: col}
@@ -194,11 +206,11 @@ type isNULL struct {
 }
 
 func IsNULL(col string) isNULL {
-	return isNULL{column: generateColumn(col), op: " IS NULL "}
+	return isNULL{column: generateColumn(col), op: " IS NULL"}
 }
 
 func NotNULL(col string) isNULL {
-	return isNULL{column: generateColumn(col), op: " IS NOT NULL "}
+	return isNULL{column: generateColumn(col), op: " IS NOT NULL"}
 }
 
 func (e isNULL) Build(builder Builder) error {
@@ -11,15 +11,20 @@ var (
 
 // From from clause
 type From struct {
-	Tables []Table
-	Joins  []Join
+	Tables  []Table
+	Joins   []Join
+	Keyword string // written before Tables, " FROM " if empty, like " USING " for DELETE
 }
 
 // Build build from clause
 func (from From) Build(builder Builder) error {
-	builder.WriteString(" FROM ")
-
 	if len(from.Tables) > 0 {
+		if from.Keyword == "" {
+			builder.WriteString(" FROM ")
+		} else {
+			builder.WriteString(from.Keyword)
+		}
+
 		for idx, table := range from.Tables {
 			if idx > 0 {
 				builder.WriteByte(',')
@@ -35,7 +40,9 @@ func (from From) Build(builder Builder) error {
 
 	for _, join := range from.Joins {
 		builder.WriteByte(' ')
-		join.Build(builder)
+		if err := join.Build(builder); err != nil {
+			return err
+		}
 	}
 
 	return nil
@@ -0,0 +1,274 @@
+package clause
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrUnsupportedFunc = errors.New("unsupported function")
+	ErrFuncArgs        = errors.New("wrong number of function args")
+)
+
+// CastType target type of Cast, mapped to the type of each dialect
+type CastType string
+
+const (
+	CastInt      CastType = "INT"
+	CastFloat    CastType = "FLOAT"
+	CastText     CastType = "TEXT"
+	CastDate     CastType = "DATE"
+	CastDateTime CastType = "DATETIME"
+)
+
+// DateUnit unit of DateAdd, DateSub and DateTrunc
+type DateUnit string
+
+const (
+	UnitSecond DateUnit = "SECOND"
+	UnitMinute DateUnit = "MINUTE"
+	UnitHour   DateUnit = "HOUR"
+	UnitDay    DateUnit = "DAY"
+	UnitMonth  DateUnit = "MONTH"
+	UnitYear   DateUnit = "YEAR"
+)
+
+// Func sql function written as Name(Args). functions returned by the constructors of clause are portable,
+// they are written by the template from Dialecter.Func if there is one.
+// Args: string is a column, Expression is built, nil is NULL and others are args, use Val() for a string arg.
+type Func struct {
+	Name     string
+	Args     []interface{}
+	Alias    string
+	portable bool
+	err      error
+}
+
+// portableFunc function with the template of Dialecter.Func, Name is the key of the template
+func portableFunc(name string, args ...interface{}) Func {
+	return Func{Name: name, Args: args, portable: true}
+}
+
+func (f Func) As(alias string) Func {
+	f.Alias = alias
+
+	return f
+}
+
+func (f Func) Build(builder Builder) error {
+	if f.err != nil {
+		return f.err
+	}
+
+	if err := buildFunc(builder, f.Name, f.Args, f.portable); err != nil {
+		return err
+	}
+
+	if f.Alias != "" {
+		builder.WriteString(" AS ")
+		builder.WriteQuoted(f.Alias)
+	}
+
+	return nil
+}
+
+// Val v is an arg even if it is a string
+func Val(v interface{}) Expr {
+	return Expr{Sql: "?", Args: []interface{}{v}}
+}
+
+func Coalesce(args ...interface{}) Func {
+	return portableFunc("COALESCE", args...)
+}
+
+func NullIf(a, b interface{}) Func {
+	return portableFunc("NULLIF", a, b)
+}
+
+func Lower(v interface{}) Func {
+	return portableFunc("LOWER", v)
+}
+
+func Upper(v interface{}) Func {
+	return portableFunc("UPPER", v)
+}
+
+func Trim(v interface{}) Func {
+	return portableFunc("TRIM", v)
+}
+
+// Length length in characters
+func Length(v interface{}) Func {
+	return portableFunc("LENGTH", v)
+}
+
+func Concat(args ...interface{}) Func {
+	return portableFunc("CONCAT", args...)
+}
+
+// Substring pos starts from 1, length is optional
+func Substring(v, pos interface{}, length ...interface{}) Func {
+	f := portableFunc("SUBSTRING", v, pos)
+	if len(length) > 0 {
+		f.Args = append(f.Args, length[0])
+	}
+
+	return f
+}
+
+func Replace(v, from, to interface{}) Func {
+	return portableFunc("REPLACE", v, from, to)
+}
+
+func Abs(v interface{}) Func {
+	return portableFunc("ABS", v)
+}
+
+func Round(v interface{}, decimals int) Func {
+	return portableFunc("ROUND", v, Expr{Sql: strconv.Itoa(decimals)})
+}
+
+// CountDistinct COUNT(DISTINCT col)
+func CountDistinct(col interface{}) WindowFunc {
+	return WindowFunc{Name: "COUNT_DISTINCT", Args: []interface{}{col}, portable: true}
+}
+
+func Cast(v interface{}, typ CastType) Func {
+	f := portableFunc("CAST_"+string(typ), v)
+
+	switch typ {
+	case CastInt, CastFloat, CastText, CastDate, CastDateTime:
+	default:
+		f.err = fmt.Errorf("%w: cast to %s", ErrUnsupportedFunc, typ)
+	}
+
+	return f
+}
+
+// Now current timestamp
+func Now() Func {
+	return portableFunc("NOW")
+}
+
+// DateAdd add n units to date v
+func DateAdd(v, n interface{}, unit DateUnit) Func {
+	return dateFunc("DATE_ADD_", unit, v, n)
+}
+
+// DateSub subtract n units from date v
+func DateSub(v, n interface{}, unit DateUnit) Func {
+	return dateFunc("DATE_SUB_", unit, v, n)
+}
+
+// DateTrunc truncate date v to unit, it returns a datetime. UnitSecond is not supported
+func DateTrunc(unit DateUnit, v interface{}) Func {
+	if unit == UnitSecond {
+		return Func{err: fmt.Errorf("%w: date trunc to %s", ErrUnsupportedFunc, unit)}
+	}
+
+	return dateFunc("DATE_TRUNC_", unit, v)
+}
+
+func dateFunc(prefix string, unit DateUnit, args ...interface{}) Func {
+	f := portableFunc(prefix+string(unit), args...)
+
+	switch unit {
+	case UnitSecond, UnitMinute, UnitHour, UnitDay, UnitMonth, UnitYear:
+	default:
+		f.err = fmt.Errorf("%w: unit %s", ErrUnsupportedFunc, unit)
+	}
+
+	return f
+}
+
+// buildFunc write function name by the template of dialect if it is portable
+func buildFunc(builder Builder, name string, args []interface{}, portable bool) error {
+	var tmpl string
+	var ok bool
+	if portable {
+		tmpl, ok = builder.Dialect().Func(name, len(args))
+	}
+	if !ok {
+		builder.WriteString(name)
+		builder.WriteByte('(')
+		if err := buildOperands(builder, args, ","); err != nil {
+			return err
+		}
+		builder.WriteByte(')')
+
+		return nil
+	}
+	if tmpl == "" {
+		return fmt.Errorf("%w: %s in %s", ErrUnsupportedFunc, name, builder.Dialect().Dialect())
+	}
+
+	for len(tmpl) > 0 {
+		start := strings.IndexByte(tmpl, '{')
+		end := strings.IndexByte(tmpl, '}')
+		if start < 0 || end < start {
+			builder.WriteString(tmpl)
+
+			break
+		}
+
+		builder.WriteString(tmpl[:start])
+
+		if token := tmpl[start+1 : end]; strings.HasPrefix(token, "*") {
+			sep := token[1:]
+			if sep == "" {
+				sep = ","
+			}
+
+			if err := buildOperands(builder, args, sep); err != nil {
+				return err
+			}
+		} else {
+			idx, err := strconv.Atoi(token)
+			if err != nil || idx >= len(args) {
+				return fmt.Errorf("%w: %s needs {%s}", ErrFuncArgs, name, token)
+			}
+
+			if err = buildOperand(builder, args[idx]); err != nil {
+				return err
+			}
+		}
+
+		tmpl = tmpl[end+1:]
+	}
+
+	return nil
+}
+
+func buildOperands(builder Builder, args []interface{}, sep string) error {
+	for idx, arg := range args {
+		if idx > 0 {
+			builder.WriteString(sep)
+		}
+
+		if err := buildOperand(builder, arg); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// buildOperand string is a column, Expression is built, nil is NULL and others are args
+func buildOperand(builder Builder, v interface{}) error {
+	switch v := v.(type) {
+	case nil:
+		builder.WriteString("NULL")
+	case string:
+		builder.WriteQuoted(Column{Name: v})
+	case Column:
+		builder.WriteQuoted(v)
+	case Expression:
+		return v.Build(builder)
+	default:
+		builder.AppendArg(v)
+	}
+
+	return nil
+}
@@ -21,7 +21,7 @@ type Join struct {
 
 func (join Join) Build(builder Builder) error {
 	if join.Expression != nil {
-		join.Expression.Build(builder)
+		return join.Expression.Build(builder)
 	} else {
 		if join.Type != "" {
 			builder.WriteString(string(join.Type))
@@ -32,7 +32,7 @@ func (join Join) Build(builder Builder) error {
 		builder.WriteQuoted(join.Table)
 
 		if len(join.ON.Exprs) > 0 {
-			join.ON.Build(builder)
+			return join.ON.Build(builder)
 		} else if len(join.USING) > 0 {
 			builder.WriteString(" USING (")
 			for idx, c := range join.USING {
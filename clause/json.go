@@ -0,0 +1,75 @@
+package clause
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// JSONPath path inside a JSON column, keys are object keys(string) or array indexes(int).
+// it is bound as an arg in the format of Dialecter.JSONPath
+type JSONPath []interface{}
+
+func (p JSONPath) Build(builder Builder) error {
+	builder.AppendArg(builder.Dialect().JSONPath(p))
+
+	return nil
+}
+
+// JSONExtract value at path of JSON column col as JSON
+func JSONExtract(col interface{}, path ...interface{}) Func {
+	return portableFunc("JSON_EXTRACT", col, JSONPath(path))
+}
+
+// JSONExtractText value at path of JSON column col as text, JSON strings are unquoted
+func JSONExtractText(col interface{}, path ...interface{}) Func {
+	return portableFunc("JSON_EXTRACT_TEXT", col, JSONPath(path))
+}
+
+// JSONContains whether JSON column col contains value, like {"a":1} contains {"a":1,"b":2}. it is not supported by sqlite
+func JSONContains(col interface{}, value interface{}) Func {
+	return jsonFunc("JSON_CONTAINS", value, false, col)
+}
+
+// JSONHasKey whether path exists in JSON column col
+func JSONHasKey(col interface{}, path ...interface{}) Func {
+	return portableFunc("JSON_HAS_KEY", col, JSONPath(path))
+}
+
+// JSONArrayContains whether the array at path of JSON column col contains value
+func JSONArrayContains(col interface{}, value interface{}, path ...interface{}) Func {
+	return jsonFunc("JSON_ARRAY_CONTAINS", value, true, col, JSONPath(path))
+}
+
+// JSONSet set value at path of JSON column col, use it as the value of Set
+func JSONSet(col interface{}, value interface{}, path ...interface{}) Func {
+	return jsonFunc("JSON_SET", value, false, col, JSONPath(path))
+}
+
+// jsonFunc value is marshalled and appended to args, []byte and json.RawMessage are JSON already
+func jsonFunc(name string, value interface{}, inArray bool, args ...interface{}) Func {
+	f := portableFunc(name, args...)
+
+	var bs []byte
+	var err error
+	switch v := value.(type) {
+	case json.RawMessage:
+		bs = v
+	case []byte:
+		bs = v
+	default:
+		bs, err = json.Marshal(value)
+	}
+	if err != nil {
+		f.err = fmt.Errorf("%w: %s value: %v", ErrUnsupportedFunc, name, err)
+
+		return f
+	}
+
+	if inArray {
+		bs = append(append([]byte{'['}, bs...), ']')
+	}
+
+	f.Args = append(f.Args, Val(string(bs)))
+
+	return f
+}
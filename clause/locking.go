@@ -0,0 +1,93 @@
+package clause
+
+import (
+	"fmt"
+
+	"github.com/meilihao/layer/dialect"
+)
+
+var (
+	ClauseLocking = "FOR"
+)
+
+const (
+	LockUpdate      = dialect.LockUpdate
+	LockNoKeyUpdate = dialect.LockNoKeyUpdate // postgres only
+	LockShare       = dialect.LockShare
+	LockKeyShare    = dialect.LockKeyShare // postgres only
+
+	LockNoWait     = dialect.LockNoWait
+	LockSkipLocked = dialect.LockSkipLocked
+)
+
+// Locking row locking clause, like `FOR UPDATE OF t1 SKIP LOCKED`
+type Locking struct {
+	Strength string
+	Tables   []string // OF tables
+	Option   string   // NOWAIT or SKIP LOCKED
+}
+
+func ForUpdate() Locking {
+	return Locking{Strength: LockUpdate}
+}
+
+func ForShare() Locking {
+	return Locking{Strength: LockShare}
+}
+
+func (l Locking) Of(tables ...string) Locking {
+	l.Tables = append(l.Tables[:len(l.Tables):len(l.Tables)], tables...)
+
+	return l
+}
+
+func (l Locking) NoWait() Locking {
+	l.Option = LockNoWait
+
+	return l
+}
+
+func (l Locking) SkipLocked() Locking {
+	l.Option = LockSkipLocked
+
+	return l
+}
+
+func (l Locking) Build(builder Builder) error {
+	if l.Strength == "" {
+		return nil
+	}
+
+	var err error
+	switch l.Option {
+	case "", LockNoWait, LockSkipLocked:
+		err = builder.Dialect().Locking(l.Strength, l.Option, len(l.Tables) > 0)
+	default:
+		err = fmt.Errorf("%w: %s", dialect.ErrUnsupportedLocking, l.Option)
+	}
+	if err != nil {
+		builder.AddError(&BuildError{Err: err})
+
+		return nil
+	}
+
+	builder.WriteString(" FOR ")
+	builder.WriteString(l.Strength)
+
+	for idx, t := range l.Tables {
+		if idx == 0 {
+			builder.WriteString(" OF ")
+		} else {
+			builder.WriteByte(',')
+		}
+
+		builder.WriteQuoted(t)
+	}
+
+	if l.Option != "" {
+		builder.WriteByte(' ')
+		builder.WriteString(l.Option)
+	}
+
+	return nil
+}
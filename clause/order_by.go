@@ -1,6 +1,9 @@
 package clause
 
-import "strings"
+import (
+	"fmt"
+	"strings"
+)
 
 var (
 	ClauseOrderBy = "ORDER BY"
@@ -12,12 +15,21 @@ type OrderBy struct {
 
 // Build build where clause
 func (orderBy OrderBy) Build(builder Builder) error {
+	if len(orderBy.Columns) == 0 {
+		return nil
+	}
+
+	builder.WriteString(" ORDER BY ")
+
+	return buildOrderColumns(builder, orderBy.Columns)
+}
+
+// buildOrderColumns "-col" is DESC, "+col" and "col" are ASC
+func buildOrderColumns(builder Builder, columns []interface{}) error {
 	var err error
 
-	for idx, column := range orderBy.Columns {
-		if idx == 0 {
-			builder.WriteString(" ORDER BY ")
-		} else if idx > 0 {
+	for idx, column := range columns {
+		if idx > 0 {
 			builder.WriteByte(',')
 		}
 
@@ -38,6 +50,8 @@ func (orderBy OrderBy) Build(builder Builder) error {
 			if err = v.Build(builder); err != nil {
 				return err
 			}
+		default:
+			builder.AddError(&BuildError{Column: fmt.Sprintf("%v", v), Err: ErrUnsupportedColumn})
 		}
 	}
 
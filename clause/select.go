@@ -1,5 +1,7 @@
 package clause
 
+import "fmt"
+
 var (
 	ClauseSelect = "SELECT"
 )
@@ -32,6 +34,8 @@ func (s Select) Build(builder Builder) error {
 				builder.WriteQuoted(Column{Name: v})
 			case Column:
 				builder.WriteQuoted(v)
+			default:
+				builder.AddError(&BuildError{Column: fmt.Sprintf("%v", v), Err: ErrUnsupportedColumn})
 			}
 		}
 	} else {
@@ -14,4 +14,13 @@ const (
 	Union         UnionType = "UNION"
 	UnionAll      UnionType = "UNION ALL"
 	UnionDistinct UnionType = "UNION DISTINCT"
+	Intersect     UnionType = "INTERSECT"
+	IntersectAll  UnionType = "INTERSECT ALL"
+	Except        UnionType = "EXCEPT"
+	ExceptAll     UnionType = "EXCEPT ALL"
 )
+
+// IsIntersect INTERSECT binds more tightly than UNION and EXCEPT
+func (t UnionType) IsIntersect() bool {
+	return t == Intersect || t == IntersectAll
+}
@@ -115,13 +115,15 @@ type AndConditions struct {
 func (and AndConditions) Build(builder Builder) error {
 	if len(and.Exprs) > 1 {
 		builder.WriteByte('(')
-		buildExprs(and.Exprs, builder, " AND ")
+		if err := buildExprs(and.Exprs, builder, " AND "); err != nil {
+			return err
+		}
 		builder.WriteByte(')')
-	} else {
-		buildExprs(and.Exprs, builder, " AND ")
+
+		return nil
 	}
 
-	return nil
+	return buildExprs(and.Exprs, builder, " AND ")
 }
 
 func Or(exprs ...Expression) Expression {
@@ -139,13 +141,15 @@ type OrConditions struct {
 func (or OrConditions) Build(builder Builder) error {
 	if len(or.Exprs) > 1 {
 		builder.WriteByte('(')
-		buildExprs(or.Exprs, builder, " OR ")
+		if err := buildExprs(or.Exprs, builder, " OR "); err != nil {
+			return err
+		}
 		builder.WriteByte(')')
-	} else {
-		buildExprs(or.Exprs, builder, " OR ")
+
+		return nil
 	}
 
-	return nil
+	return buildExprs(or.Exprs, builder, " OR ")
 }
 
 // Cond defines an interface
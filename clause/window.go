@@ -0,0 +1,371 @@
+package clause
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+var (
+	ClauseWindow = "WINDOW"
+
+	ErrEmptyWindowName = errors.New("named window needs a name")
+)
+
+// FrameType unit of window frame
+type FrameType string
+
+const (
+	FrameRows   FrameType = "ROWS"
+	FrameRange  FrameType = "RANGE"
+	FrameGroups FrameType = "GROUPS" // not supported by mysql
+)
+
+// BoundType type of frame bound
+type BoundType string
+
+const (
+	BoundUnboundedPreceding BoundType = "UNBOUNDED PRECEDING"
+	BoundPreceding          BoundType = "PRECEDING"
+	BoundCurrentRow         BoundType = "CURRENT ROW"
+	BoundFollowing          BoundType = "FOLLOWING"
+	BoundUnboundedFollowing BoundType = "UNBOUNDED FOLLOWING"
+)
+
+// FrameBound start or end of window frame
+type FrameBound struct {
+	Type   BoundType
+	Offset interface{} // for PRECEDING and FOLLOWING, int is written as is, Expression is built, others are args
+}
+
+func UnboundedPreceding() FrameBound {
+	return FrameBound{Type: BoundUnboundedPreceding}
+}
+
+func Preceding(offset interface{}) FrameBound {
+	return FrameBound{Type: BoundPreceding, Offset: offset}
+}
+
+func CurrentRow() FrameBound {
+	return FrameBound{Type: BoundCurrentRow}
+}
+
+func Following(offset interface{}) FrameBound {
+	return FrameBound{Type: BoundFollowing, Offset: offset}
+}
+
+func UnboundedFollowing() FrameBound {
+	return FrameBound{Type: BoundUnboundedFollowing}
+}
+
+func (b FrameBound) Build(builder Builder) error {
+	if b.Type == BoundPreceding || b.Type == BoundFollowing {
+		switch v := b.Offset.(type) {
+		case nil:
+			return fmt.Errorf("no offset for %s", b.Type)
+		case int:
+			builder.WriteString(strconv.Itoa(v))
+		case Expression:
+			if err := v.Build(builder); err != nil {
+				return err
+			}
+		default:
+			builder.AppendArg(v)
+		}
+		builder.WriteByte(' ')
+	}
+
+	builder.WriteString(string(b.Type))
+
+	return nil
+}
+
+// Frame window frame, it is `Type Start` if End is nil
+type Frame struct {
+	Type  FrameType
+	Start FrameBound
+	End   *FrameBound
+}
+
+func (f Frame) Build(builder Builder) error {
+	if err := builder.Dialect().Frame(string(f.Type)); err != nil {
+		builder.AddError(&BuildError{Err: err})
+
+		return nil
+	}
+
+	builder.WriteString(string(f.Type))
+	builder.WriteByte(' ')
+
+	if f.End == nil {
+		return f.Start.Build(builder)
+	}
+
+	builder.WriteString("BETWEEN ")
+	if err := f.Start.Build(builder); err != nil {
+		return err
+	}
+	builder.WriteString(" AND ")
+
+	return f.End.Build(builder)
+}
+
+// Window window specification, used by OVER and WINDOW
+type Window struct {
+	Base        string        // name of the window which this one is based on
+	PartitionBy []interface{} // string, Column or Expression
+	OrderBy     []interface{} // same as OrderBy.Columns, like "-created_at"
+	Frame       *Frame
+}
+
+func NewWindow() Window {
+	return Window{}
+}
+
+func (w Window) Partition(cols ...interface{}) Window {
+	w.PartitionBy = append(w.PartitionBy[:len(w.PartitionBy):len(w.PartitionBy)], cols...)
+
+	return w
+}
+
+func (w Window) Order(cols ...interface{}) Window {
+	w.OrderBy = append(w.OrderBy[:len(w.OrderBy):len(w.OrderBy)], cols...)
+
+	return w
+}
+
+func (w Window) Rows(start, end FrameBound) Window {
+	w.Frame = &Frame{Type: FrameRows, Start: start, End: &end}
+
+	return w
+}
+
+func (w Window) Range(start, end FrameBound) Window {
+	w.Frame = &Frame{Type: FrameRange, Start: start, End: &end}
+
+	return w
+}
+
+func (w Window) Groups(start, end FrameBound) Window {
+	w.Frame = &Frame{Type: FrameGroups, Start: start, End: &end}
+
+	return w
+}
+
+// Build build the window specification without parentheses
+func (w Window) Build(builder Builder) error {
+	space := false
+	sep := func() {
+		if space {
+			builder.WriteByte(' ')
+		}
+		space = true
+	}
+
+	if w.Base != "" {
+		sep()
+		builder.WriteQuoted(w.Base)
+	}
+
+	for idx, col := range w.PartitionBy {
+		if idx == 0 {
+			sep()
+			builder.WriteString("PARTITION BY ")
+		} else {
+			builder.WriteByte(',')
+		}
+
+		switch v := col.(type) {
+		case string:
+			builder.WriteQuoted(Column{Name: v})
+		case Column:
+			builder.WriteQuoted(v)
+		case Expression:
+			if err := v.Build(builder); err != nil {
+				return err
+			}
+		default:
+			builder.AddError(&BuildError{Column: fmt.Sprintf("%v", v), Err: ErrUnsupportedColumn})
+		}
+	}
+
+	if len(w.OrderBy) > 0 {
+		sep()
+		builder.WriteString("ORDER BY ")
+		if err := buildOrderColumns(builder, w.OrderBy); err != nil {
+			return err
+		}
+	}
+
+	if w.Frame != nil {
+		sep()
+
+		return w.Frame.Build(builder)
+	}
+
+	return nil
+}
+
+// WindowFunc window function, like `ROW_NUMBER() OVER (PARTITION BY a ORDER BY b) AS rn`
+type WindowFunc struct {
+	Name     string
+	Args     []interface{} // same as Func.Args
+	Window   *Window       // OVER (...)
+	Named    string        // OVER name, used if Window is nil
+	Alias    string
+	portable bool // see Func
+}
+
+// Fn call window or aggregate function name, it is written as is without the template of Dialecter.Func
+func Fn(name string, args ...interface{}) WindowFunc {
+	return WindowFunc{Name: name, Args: args}
+}
+
+func RowNumber() WindowFunc {
+	return Fn("ROW_NUMBER")
+}
+
+func Rank() WindowFunc {
+	return Fn("RANK")
+}
+
+func DenseRank() WindowFunc {
+	return Fn("DENSE_RANK")
+}
+
+func PercentRank() WindowFunc {
+	return Fn("PERCENT_RANK")
+}
+
+func CumeDist() WindowFunc {
+	return Fn("CUME_DIST")
+}
+
+func Ntile(n int) WindowFunc {
+	return Fn("NTILE", Expr{Sql: strconv.Itoa(n)})
+}
+
+// Lag args are offset and default value
+func Lag(col interface{}, args ...interface{}) WindowFunc {
+	return Fn("LAG", append([]interface{}{col}, args...)...)
+}
+
+// Lead args are offset and default value
+func Lead(col interface{}, args ...interface{}) WindowFunc {
+	return Fn("LEAD", append([]interface{}{col}, args...)...)
+}
+
+func FirstValue(col interface{}) WindowFunc {
+	return Fn("FIRST_VALUE", col)
+}
+
+func LastValue(col interface{}) WindowFunc {
+	return Fn("LAST_VALUE", col)
+}
+
+func NthValue(col interface{}, n int) WindowFunc {
+	return Fn("NTH_VALUE", col, Expr{Sql: strconv.Itoa(n)})
+}
+
+func Sum(col interface{}) WindowFunc {
+	return Fn("SUM", col)
+}
+
+func Avg(col interface{}) WindowFunc {
+	return Fn("AVG", col)
+}
+
+func Min(col interface{}) WindowFunc {
+	return Fn("MIN", col)
+}
+
+func Max(col interface{}) WindowFunc {
+	return Fn("MAX", col)
+}
+
+// Count COUNT(*) if col is nil
+func Count(col interface{}) WindowFunc {
+	if col == nil {
+		return Fn("COUNT", Expr{Sql: "*"})
+	}
+
+	return Fn("COUNT", col)
+}
+
+func (f WindowFunc) Over(w Window) WindowFunc {
+	f.Window = &w
+
+	return f
+}
+
+// OverName use a window defined by WINDOW clause
+func (f WindowFunc) OverName(name string) WindowFunc {
+	f.Window = nil
+	f.Named = name
+
+	return f
+}
+
+func (f WindowFunc) As(alias string) WindowFunc {
+	f.Alias = alias
+
+	return f
+}
+
+func (f WindowFunc) Build(builder Builder) error {
+	if err := buildFunc(builder, f.Name, f.Args, f.portable); err != nil {
+		return err
+	}
+
+	if f.Window != nil {
+		builder.WriteString(" OVER (")
+		if err := f.Window.Build(builder); err != nil {
+			return err
+		}
+		builder.WriteByte(')')
+	} else if f.Named != "" {
+		builder.WriteString(" OVER ")
+		builder.WriteQuoted(f.Named)
+	}
+
+	if f.Alias != "" {
+		builder.WriteString(" AS ")
+		builder.WriteQuoted(f.Alias)
+	}
+
+	return nil
+}
+
+// NamedWindow window defined by WINDOW clause
+type NamedWindow struct {
+	Name string
+	Window
+}
+
+// Windows WINDOW clause
+type Windows struct {
+	Windows []NamedWindow
+}
+
+func (ws Windows) Build(builder Builder) error {
+	for idx, w := range ws.Windows {
+		if w.Name == "" {
+			return ErrEmptyWindowName
+		}
+
+		if idx == 0 {
+			builder.WriteString(" WINDOW ")
+		} else {
+			builder.WriteByte(',')
+		}
+
+		builder.WriteQuoted(w.Name)
+		builder.WriteString(" AS (")
+		if err := w.Window.Build(builder); err != nil {
+			return err
+		}
+		builder.WriteByte(')')
+	}
+
+	return nil
+}
@@ -0,0 +1,67 @@
+package clause
+
+import "errors"
+
+var (
+	ClauseWith = "WITH"
+
+	ErrEmptyCTE = errors.New("CTE needs a name and a query")
+)
+
+// CTE common table expression
+type CTE struct {
+	Name      string
+	Columns   []string
+	Recursive bool
+	Query     interface{} // sub query, built by Builder.AppendArg
+}
+
+// With WITH clause, it is RECURSIVE if any CTE is recursive
+type With struct {
+	CTEs []CTE
+}
+
+func (with With) Build(builder Builder) error {
+	if len(with.CTEs) == 0 {
+		return nil
+	}
+
+	builder.WriteString("WITH ")
+	for _, cte := range with.CTEs {
+		if cte.Recursive {
+			builder.WriteString("RECURSIVE ")
+			break
+		}
+	}
+
+	for idx, cte := range with.CTEs {
+		if cte.Name == "" || cte.Query == nil {
+			return ErrEmptyCTE
+		}
+
+		if idx > 0 {
+			builder.WriteByte(',')
+		}
+
+		builder.WriteQuoted(cte.Name)
+
+		if len(cte.Columns) > 0 {
+			builder.WriteString(" (")
+			for i, c := range cte.Columns {
+				if i > 0 {
+					builder.WriteByte(',')
+				}
+				builder.WriteQuoted(Column{Name: c})
+			}
+			builder.WriteByte(')')
+		}
+
+		builder.WriteString(" AS (")
+		builder.AppendArg(cte.Query)
+		builder.WriteByte(')')
+	}
+
+	builder.WriteByte(' ')
+
+	return nil
+}
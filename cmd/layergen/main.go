@@ -0,0 +1,139 @@
+// Command layergen generates typed column accessors of models for go generate:
+//
+//	//go:generate go run github.com/meilihao/layer/cmd/layergen -type User,Dept
+//
+// it builds a temporary program in the package directory, which imports the package and
+// reads the models by schema.Parse, so the columns are exactly the ones used at runtime.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+var (
+	types  = flag.String("type", "", "comma-separated list of model names, required")
+	output = flag.String("output", "", "output file name, default is <package>_layer.go")
+	dir    = flag.String("dir", ".", "directory of the package")
+)
+
+var mainTmpl = template.Must(template.New("main").Parse(`package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"log"
+
+	"github.com/meilihao/layer/gen"
+	m {{printf "%q" .PkgPath}}
+)
+
+func main() {
+	var b bytes.Buffer
+	if err := gen.Generate(&b, gen.Config{Package: {{printf "%q" .Package}}, PkgPath: {{printf "%q" .PkgPath}}},{{range .Types}}
+		m.{{.}}{},{{end}}
+	); err != nil {
+		log.Fatal(err)
+	}
+
+	if err := ioutil.WriteFile({{printf "%q" .Output}}, b.Bytes(), 0644); err != nil {
+		log.Fatal(err)
+	}
+}
+`))
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("layergen: ")
+	flag.Parse()
+
+	if *types == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if err := run(); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func run() error {
+	abs, err := filepath.Abs(*dir)
+	if err != nil {
+		return err
+	}
+
+	out, err := goCmd(abs, "list", "-f", "{{.ImportPath}} {{.Name}}", ".")
+	if err != nil {
+		return err
+	}
+	fields := strings.Fields(out)
+	if len(fields) != 2 {
+		return fmt.Errorf("unexpected go list output: %s", out)
+	}
+	pkgPath, pkgName := fields[0], fields[1]
+	if pkgName == "main" {
+		return fmt.Errorf("package main can not be imported, move models to another package")
+	}
+
+	if *output == "" {
+		*output = pkgName + "_layer.go"
+	}
+	if !filepath.IsAbs(*output) {
+		*output = filepath.Join(abs, *output)
+	}
+
+	var ts []string
+	for _, t := range strings.Split(*types, ",") {
+		if t = strings.TrimSpace(t); t != "" {
+			ts = append(ts, t)
+		}
+	}
+
+	// "_" prefixed directory is ignored by ./... of go tool
+	tmp, err := ioutil.TempDir(abs, "_layergen")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmp)
+
+	var src bytes.Buffer
+	if err = mainTmpl.Execute(&src, map[string]interface{}{
+		"PkgPath": pkgPath,
+		"Package": pkgName,
+		"Types":   ts,
+		"Output":  *output,
+	}); err != nil {
+		return err
+	}
+	if err = ioutil.WriteFile(filepath.Join(tmp, "main.go"), src.Bytes(), 0644); err != nil {
+		return err
+	}
+
+	_, err = goCmd(abs, "run", "./"+filepath.Base(tmp))
+
+	return err
+}
+
+func goCmd(dir string, args ...string) (string, error) {
+	cmd := exec.Command("go", args...)
+	cmd.Dir = dir
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("go %s: %v\n%s", args[0], err, stderr.String())
+	}
+
+	return stdout.String(), nil
+}
@@ -114,12 +114,14 @@ func (se *CreateSession) Create(value interface{}) (interface{}, error) {
 	if se.err != nil {
 		return nil, se.err
 	}
-	se.value, _ = utils.PtrValue(value)
+	if se.value, _, se.err = utils.PtrValue(value); se.err != nil {
+		return nil, se.err
+	}
 	if se.table == "" {
 		se.table = se.schema.RawName
 	}
 
-	se.clauses[clause.ClauseInsert] = clause.Insert{Table: clause.Table{Name: se.table}}
+	se.clauses[clause.ClauseInsert] = clause.Insert{Table: modelTable(se.schema, se.table)}
 
 	if se.schema.AutoincrColumn != nil && se.l.dialecter.HasReturning() {
 		se.returning = se.l.dialecter.Returning(se.l.dialecter.Queto(se.schema.AutoincrColumn.DBName))
@@ -169,11 +171,9 @@ func (se *CreateSession) Create(value interface{}) (interface{}, error) {
 	}
 
 	now := time.Now()
-	if se.debug {
-		log.Info().Msgf("[%f] %s", time.Now().Sub(now).Seconds(), se.builder.String())
-	}
-
 	if se.dryRun {
+		se.l.logQuery(se.context, &QueryEvent{Op: OpCreate, Table: se.table, SQL: se.builder.String()}, se.debug)
+
 		return true, nil
 	}
 
@@ -185,9 +185,9 @@ func (se *CreateSession) create(now time.Time) (interface{}, error) {
 		se.context = context.Background()
 	}
 
-	stmt, err := se.l.db.PrepareContext(se.context, se.builder.String())
+	stmt, err := se.l.conn().PrepareContext(se.context, se.builder.String())
 	if err != nil {
-		return nil, err
+		return nil, se.l.TranslateError(err)
 	}
 
 	defer func() {
@@ -256,21 +256,27 @@ func (se *CreateSession) create1(s *sql.Stmt, v reflect.Value, now time.Time) (e
 		a = append(a, i)
 	}
 
-	if se.debug {
-		log.Info().Msg(se.l.dialecter.Explain(se.builder.String(), a))
-	}
+	var n int64
+	ev := &QueryEvent{Op: OpCreate, Table: se.table, SQL: se.builder.String(), Args: a}
+	ctx := se.l.startQuery(se.context, ev)
+	defer func() {
+		ev.RowsAffected, ev.Err = n, err
+		se.l.endQuery(ctx, ev, se.debug)
+		err = ev.Err
+	}()
 
 	c := se.schema.AutoincrColumn
 	if se.returning != "" {
 		var id int64
-		if err = s.QueryRowContext(se.context, a...).Scan(&id); err != nil {
+		if err = s.QueryRowContext(ctx, a...).Scan(&id); err != nil {
 			return err
 		} else if !c.SetInteger(v, id) {
 			return c.ErrSet()
 		}
+		n = 1
 		return
 	}
-	r, err := s.ExecContext(se.context, a...)
+	r, err := s.ExecContext(ctx, a...)
 	if err != nil {
 		return
 	}
@@ -281,8 +287,7 @@ func (se *CreateSession) create1(s *sql.Stmt, v reflect.Value, now time.Time) (e
 			return c.ErrSet()
 		}
 	}
-	n, err := r.RowsAffected()
-	if err != nil {
+	if n, err = r.RowsAffected(); err != nil {
 		return
 	}
 	if n != 1 {
@@ -0,0 +1,422 @@
+package layer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/meilihao/layer/clause"
+	"github.com/meilihao/layer/dialect"
+	"github.com/meilihao/layer/schema"
+	"github.com/meilihao/layer/utils"
+)
+
+var (
+	ErrUnsupportedDDL = errors.New("layer : dialect does not support DDL")
+	ErrNoColumnType   = errors.New("layer : no column type, set it by tag type")
+)
+
+// DDLOption option of DDL
+type DDLOption func(*ddlBuilder)
+
+// DDLIfNotExists add IF NOT EXISTS to CREATE TABLE and CREATE INDEX
+func DDLIfNotExists() DDLOption {
+	return func(d *ddlBuilder) {
+		d.ifNotExists = true
+	}
+}
+
+// DDLNoForeignKey skip foreign keys of many2one
+func DDLNoForeignKey() DDLOption {
+	return func(d *ddlBuilder) {
+		d.noForeignKey = true
+	}
+}
+
+type ddlBuilder struct {
+	l            *Layer
+	d            dialect.DDLer
+	sc           *schema.Schema
+	ifNotExists  bool
+	noForeignKey bool
+}
+
+// DDL returns statements creating the table of model: CREATE TABLE with primary key, unique and foreign keys(many2one),
+// then COMMENT ON(postgres) and CREATE INDEX.
+// column types come from tag type, or DataType, size and precision by dialect.DDLer if tag type is empty or a DataType.
+func (l *Layer) DDL(model interface{}, opts ...DDLOption) ([]string, error) {
+	d, ok := l.dialecter.(dialect.DDLer)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedDDL, l.dialecter.Dialect())
+	}
+
+	sc, err := schema.Parse(model, l.opts.nameMapper)
+	if err != nil {
+		return nil, err
+	}
+
+	b := &ddlBuilder{l: l, d: d, sc: sc}
+	for _, opt := range opts {
+		opt(b)
+	}
+
+	return b.build()
+}
+
+// CreateTable execute DDL of models in order, so parents of many2one should be in front of their children
+func (l *Layer) CreateTable(ctx context.Context, models []interface{}, opts ...DDLOption) error {
+	for _, m := range models {
+		stmts, err := l.DDL(m, opts...)
+		if err != nil {
+			return err
+		}
+
+		for _, stmt := range stmts {
+			if _, err = l.execContext(ctx, &QueryEvent{Op: OpExec, SQL: stmt}); err != nil {
+				return l.TranslateError(err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// ddlIndex index of columns with the same name of tag index or unique
+type ddlIndex struct {
+	name    string
+	unique  bool
+	columns []*schema.Column
+}
+
+// ddlForeignKey foreign key of many2one
+type ddlForeignKey struct {
+	name    string
+	columns []*schema.Column
+	parent  *schema.Schema
+}
+
+func (b *ddlBuilder) build() ([]string, error) {
+	sb := NewSQLBuilder(b.l, nil, 256)
+
+	sb.WriteString("CREATE TABLE ")
+	if b.ifNotExists {
+		sb.WriteString("IF NOT EXISTS ")
+	}
+	sb.WriteQuoted(b.table())
+	sb.WriteString(" (")
+
+	var inlinePK bool
+	for idx, c := range b.sc.Columns {
+		if idx > 0 {
+			sb.WriteByte(',')
+		}
+
+		pk, err := b.writeColumn(sb, c, true)
+		if err != nil {
+			return nil, err
+		}
+		inlinePK = inlinePK || pk
+	}
+
+	if len(b.sc.PrimaryColumns) > 0 && !inlinePK {
+		sb.WriteString(",PRIMARY KEY ")
+		b.writeColumns(sb, b.sc.PrimaryColumns)
+	}
+
+	inlineIndex := b.d.HasInlineIndex()
+	if inlineIndex {
+		for _, idx := range b.indexes() {
+			sb.WriteByte(',')
+			b.writeIndex(sb, idx)
+		}
+	}
+
+	if !b.noForeignKey {
+		for _, fk := range b.foreignKeys() {
+			sb.WriteByte(',')
+			b.writeForeignKey(sb, fk)
+		}
+	}
+	sb.WriteByte(')')
+
+	stmts := []string{sb.String()}
+
+	if b.d.Comment() == dialect.CommentOn {
+		for _, c := range b.sc.Columns {
+			if f := columnField(c); f.Comment != "" {
+				stmts = append(stmts, b.commentOn(c, f.Comment))
+			}
+		}
+	}
+
+	if !inlineIndex {
+		for _, idx := range b.indexes() {
+			stmts = append(stmts, b.createIndex(idx))
+		}
+	}
+
+	return stmts, nil
+}
+
+func (b *ddlBuilder) table() clause.Table {
+	return clause.Table{Schema: b.sc.TableSchema, Name: b.sc.RawName}
+}
+
+// columnField props of relationship column are from the relationship field, c.Field is the pk of the parent
+func columnField(c *schema.Column) *schema.Field {
+	if isRelColumn(c) {
+		return c.Parent
+	}
+
+	return c.Field
+}
+
+func isRelColumn(c *schema.Column) bool {
+	return c.Parent != nil && c.Parent.Relationship != nil
+}
+
+// isUniqueColumn unique without name is a column constraint, the named one is a unique index
+func isUniqueColumn(c *schema.Column) bool {
+	name, ok := columnField(c).TagSettings[schema.TagUnique]
+	return ok && name == ""
+}
+
+// writeColumn write definition of c, inlinePK is true if it contains PRIMARY KEY
+func (b *ddlBuilder) writeColumn(sb *SQLBuilder, c *schema.Column, unique bool) (inlinePK bool, err error) {
+	f := columnField(c)
+
+	autoIncr := c.IsPK && c.Field.AutoIncr
+	typ, err := b.columnType(c, autoIncr)
+	if err != nil {
+		return false, err
+	}
+
+	sb.WriteQuoted(clause.Column{Name: c.RawName})
+	sb.WriteString(" " + typ)
+	if f.NotNull || c.IsPK {
+		sb.WriteString(" NOT NULL")
+	}
+	if v, ok := b.defaultValue(f, typ); ok && !isRelColumn(c) {
+		sb.WriteString(" DEFAULT " + v)
+	}
+	if autoIncr {
+		if s, pk := b.d.AutoIncr(); s != "" {
+			sb.WriteString(" " + s)
+			inlinePK = pk
+		}
+	}
+	if unique && isUniqueColumn(c) {
+		sb.WriteString(" UNIQUE")
+	}
+	if f.Comment != "" && b.d.Comment() == dialect.CommentInline {
+		sb.WriteString(" COMMENT " + b.d.Literal(f.Comment))
+	}
+
+	return inlinePK, nil
+}
+
+func (b *ddlBuilder) writeColumns(sb *SQLBuilder, cols []*schema.Column) {
+	sb.WriteByte('(')
+	for idx, c := range cols {
+		if idx > 0 {
+			sb.WriteByte(',')
+		}
+		sb.WriteQuoted(clause.Column{Name: c.RawName})
+	}
+	sb.WriteByte(')')
+}
+
+// indexes of tag index and named unique, unnamed index is idx_<table>_<column>
+func (b *ddlBuilder) indexes() []*ddlIndex {
+	var indexes []*ddlIndex
+	byName := map[string]*ddlIndex{}
+
+	add := func(name string, unique bool, c *schema.Column) {
+		idx := byName[name]
+		if idx == nil {
+			idx = &ddlIndex{name: name, unique: unique}
+			byName[name] = idx
+			indexes = append(indexes, idx)
+		}
+		idx.columns = append(idx.columns, c)
+	}
+
+	for _, c := range b.sc.Columns {
+		f := columnField(c)
+		if name, ok := f.TagSettings[schema.TagUnique]; ok && name != "" {
+			add(name, true, c)
+		}
+		if name, ok := f.TagSettings[schema.TagIndex]; ok {
+			if name == "" {
+				name = "idx_" + b.sc.DBName + "_" + c.DBName
+			}
+			add(name, false, c)
+		}
+	}
+
+	return indexes
+}
+
+// foreignKeys of many2one, named fk_<table>_<field>
+func (b *ddlBuilder) foreignKeys() []*ddlForeignKey {
+	var fks []*ddlForeignKey
+	byField := map[*schema.Field]*ddlForeignKey{}
+
+	for _, c := range b.sc.Columns {
+		if !isRelColumn(c) || c.Parent.Relationship.Type != schema.RelMany2One {
+			continue
+		}
+
+		fk := byField[c.Parent]
+		if fk == nil {
+			fk = &ddlForeignKey{
+				name:   "fk_" + b.sc.DBName + "_" + b.l.opts.nameMapper.EntityMap(c.Parent.RawName),
+				parent: c.Parent.Relationship.Schema,
+			}
+			byField[c.Parent] = fk
+			fks = append(fks, fk)
+		}
+		fk.columns = append(fk.columns, c)
+	}
+
+	return fks
+}
+
+// writeForeignKey write CONSTRAINT name FOREIGN KEY (columns) REFERENCES parent (pk)
+func (b *ddlBuilder) writeForeignKey(sb *SQLBuilder, fk *ddlForeignKey) {
+	sb.WriteString("CONSTRAINT ")
+	sb.WriteString(b.l.dialecter.Queto(fk.name))
+	sb.WriteString(" FOREIGN KEY ")
+	b.writeColumns(sb, fk.columns)
+	sb.WriteString(" REFERENCES ")
+	sb.WriteQuoted(clause.Table{Schema: fk.parent.TableSchema, Name: fk.parent.RawName})
+	sb.WriteByte(' ')
+	b.writeColumns(sb, fk.parent.PrimaryColumns)
+}
+
+// writeIndex write UNIQUE KEY name (columns) or INDEX name (columns) in CREATE TABLE, see dialect.DDLer.HasInlineIndex
+func (b *ddlBuilder) writeIndex(sb *SQLBuilder, idx *ddlIndex) {
+	if idx.unique {
+		sb.WriteString("UNIQUE KEY ")
+	} else {
+		sb.WriteString("INDEX ")
+	}
+	sb.WriteString(b.l.dialecter.Queto(idx.name))
+	sb.WriteByte(' ')
+	b.writeColumns(sb, idx.columns)
+}
+
+// createIndex CREATE INDEX of idx, for the dialects without inline indexes and indexes added by migrate
+func (b *ddlBuilder) createIndex(idx *ddlIndex) string {
+	sb := NewSQLBuilder(b.l, nil, 64)
+	sb.WriteString("CREATE ")
+	if idx.unique {
+		sb.WriteString("UNIQUE ")
+	}
+	sb.WriteString("INDEX ")
+	if b.ifNotExists {
+		sb.WriteString("IF NOT EXISTS ")
+	}
+	sb.WriteString(b.l.dialecter.Queto(idx.name))
+	sb.WriteString(" ON ")
+	sb.WriteQuoted(b.table())
+	sb.WriteByte(' ')
+	b.writeColumns(sb, idx.columns)
+
+	return sb.String()
+}
+
+// commentOn COMMENT ON COLUMN of postgres, empty comment removes it
+func (b *ddlBuilder) commentOn(c *schema.Column, comment string) string {
+	sb := NewSQLBuilder(b.l, nil, 64)
+	sb.WriteString("COMMENT ON COLUMN ")
+	sb.WriteQuoted(b.table())
+	sb.WriteByte('.')
+	sb.WriteQuoted(clause.Column{Name: c.RawName})
+	if comment == "" {
+		sb.WriteString(" IS NULL")
+	} else {
+		sb.WriteString(" IS " + b.d.Literal(comment))
+	}
+
+	return sb.String()
+}
+
+// columnType type of column c. tag type is used as is unless it is a schema.DataType, which is mapped by the dialect
+func (b *ddlBuilder) columnType(c *schema.Column, autoIncr bool) (string, error) {
+	f := c.Field
+	if typ := f.TagSettings[schema.TagType]; typ != "" {
+		switch schema.DataType(typ) {
+		case schema.Bool, schema.Int, schema.Uint, schema.Float, schema.String, schema.Time, schema.Bytes:
+		default:
+			return typ, nil
+		}
+	}
+
+	dataType := string(f.DataType)
+	switch {
+	case f.IsJSON:
+		dataType = "json"
+	case f.IsXML:
+		dataType = "xml"
+	case f.Version:
+		// DataType of version is the name of its type
+		if utils.IsUints(f.IndirectFieldType.Kind()) {
+			dataType = string(schema.Uint)
+		} else {
+			dataType = string(schema.Int)
+		}
+	}
+
+	size := f.Size
+	if _, ok := f.TagSettings[schema.TagSize]; !ok && f.DataType == schema.Float && f.Precision > 0 {
+		size = 10
+	}
+
+	if typ := b.d.ColumnType(dataType, size, f.Precision, autoIncr); typ != "" {
+		return typ, nil
+	}
+
+	return "", fmt.Errorf("%w: %s.%s(%s)", ErrNoColumnType, b.sc.Name, c.RawName, f.FieldType)
+}
+
+// columnDefault DEFAULT written by writeColumn, null is no default
+func (b *ddlBuilder) columnDefault(c *schema.Column) (string, bool) {
+	if isRelColumn(c) {
+		return "", false
+	}
+
+	typ, err := b.columnType(c, false)
+	if err != nil {
+		return "", false
+	}
+
+	v, ok := b.defaultValue(columnField(c), typ)
+	if !ok || strings.EqualFold(v, "null") {
+		return "", false
+	}
+
+	return v, true
+}
+
+// defaultValue DEFAULT of f whose column type is typ, strings are quoted,
+// functions and null are written as is or adjusted by the dialect
+func (b *ddlBuilder) defaultValue(f *schema.Field, typ string) (string, bool) {
+	if !f.HasDefaultValue {
+		return "", false
+	}
+
+	v := f.DefaultValue
+	isExpr := strings.Contains(v, "(") && strings.Contains(v, ")") || strings.EqualFold(v, "null")
+	if f.IndirectFieldType.Kind() == reflect.String && !isExpr {
+		return b.d.Literal(v), true
+	}
+
+	if v == "" {
+		return "", false
+	}
+
+	return b.d.DefaultValue(typ, v), true
+}
@@ -0,0 +1,102 @@
+package layer
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type ddlDept struct {
+	Id   int64  `layer:";pk;autoincr"`
+	Name string `layer:";size=64;notnull;unique;comment=dept's name"`
+}
+
+type ddlAddr struct {
+	City   string `layer:";size=32;index=idx_ddl_user_addr"`
+	Street string `layer:";index=idx_ddl_user_addr"`
+}
+
+type ddlUser struct {
+	Id        int64    `layer:";pk;autoincr"`
+	Email     string   `layer:";size=128;notnull;unique=uk_ddl_user_email"`
+	Age       uint8    `layer:";default=18"`
+	Score     float64  `layer:";precision=2"`
+	Status    string   `layer:";size=16;default=active;index"`
+	Tags      []string `layer:";json"`
+	Dept      *ddlDept `layer:";many2one;notnull;comment=dept"`
+	Addr      ddlAddr  `layer:";embedded=Addr"`
+	Avatar    []byte
+	Version   int       `layer:";version"`
+	CreatedAt time.Time `layer:";default=CURRENT_TIMESTAMP"`
+}
+
+type ddlTyped struct {
+	Id        int       `layer:";pk;type=INTEGER"`
+	Note      string    `layer:";type=MEDIUMTEXT;index"`
+	Code      int       `layer:";type=string;size=8"`
+	UpdatedAt time.Time `layer:";type=TIMESTAMP(6);default=now()"`
+}
+
+type ddlBad struct {
+	Id  int `layer:";pk"`
+	Any interface{}
+}
+
+func TestLayer_DDL(t *testing.T) {
+	stmts, err := l.DDL(&ddlDept{})
+	assert.NoError(t, err)
+	assert.EqualValues(t, []string{
+		"CREATE TABLE `ddl_dept` (`id` BIGINT NOT NULL AUTO_INCREMENT,`name` VARCHAR(64) NOT NULL UNIQUE COMMENT 'dept''s name',PRIMARY KEY (`id`))",
+	}, stmts)
+
+	// mysql indexes are in CREATE TABLE, so IF NOT EXISTS covers them
+	stmts, err = l.DDL(&ddlUser{}, DDLIfNotExists())
+	assert.NoError(t, err)
+	assert.EqualValues(t, []string{
+		"CREATE TABLE IF NOT EXISTS `ddl_user` (`id` BIGINT NOT NULL AUTO_INCREMENT,`email` VARCHAR(128) NOT NULL,`age` TINYINT UNSIGNED DEFAULT 18," +
+			"`score` DECIMAL(10,2),`status` VARCHAR(16) DEFAULT 'active',`tags` JSON,`dept_id` BIGINT NOT NULL COMMENT 'dept'," +
+			"`addr_city` VARCHAR(32),`addr_street` VARCHAR(255),`avatar` LONGBLOB,`version` BIGINT,`created_at` DATETIME(3) DEFAULT CURRENT_TIMESTAMP(3)," +
+			"PRIMARY KEY (`id`),UNIQUE KEY `uk_ddl_user_email` (`email`),INDEX `idx_ddl_user_status` (`status`),INDEX `idx_ddl_user_addr` (`addr_city`,`addr_street`),CONSTRAINT `fk_ddl_user_dept` FOREIGN KEY (`dept_id`) REFERENCES `ddl_dept` (`id`))",
+	}, stmts)
+
+	stmts, err = pg.DDL(&ddlUser{}, DDLNoForeignKey())
+	assert.NoError(t, err)
+	assert.EqualValues(t, []string{
+		`CREATE TABLE "ddl_user" ("id" BIGSERIAL NOT NULL,"email" VARCHAR(128) NOT NULL,"age" SMALLINT DEFAULT 18,` +
+			`"score" NUMERIC(10,2),"status" VARCHAR(16) DEFAULT 'active',"tags" JSONB,"dept_id" BIGINT NOT NULL,` +
+			`"addr_city" VARCHAR(32),"addr_street" TEXT,"avatar" BYTEA,"version" BIGINT,"created_at" TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,` +
+			`PRIMARY KEY ("id"))`,
+		`COMMENT ON COLUMN "ddl_user"."dept_id" IS 'dept'`,
+		`CREATE UNIQUE INDEX "uk_ddl_user_email" ON "ddl_user" ("email")`,
+		`CREATE INDEX "idx_ddl_user_status" ON "ddl_user" ("status")`,
+		`CREATE INDEX "idx_ddl_user_addr" ON "ddl_user" ("addr_city","addr_street")`,
+	}, stmts)
+
+	stmts, err = lite.DDL(&ddlDept{})
+	assert.NoError(t, err)
+	assert.EqualValues(t, []string{
+		`CREATE TABLE "ddl_dept" ("id" INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,"name" VARCHAR(64) NOT NULL UNIQUE)`,
+	}, stmts)
+
+	stmts, err = lite.DDL(&ddlTyped{}, DDLIfNotExists())
+	assert.NoError(t, err)
+	assert.EqualValues(t, []string{
+		`CREATE TABLE IF NOT EXISTS "ddl_typed" ("id" INTEGER NOT NULL,"note" MEDIUMTEXT,"code" VARCHAR(8),"updated_at" TIMESTAMP(6) DEFAULT (now()),PRIMARY KEY ("id"))`,
+		`CREATE INDEX IF NOT EXISTS "idx_ddl_typed_note" ON "ddl_typed" ("note")`,
+	}, stmts)
+	// the parenthesized DEFAULT is accepted by sqlite
+	assert.NoError(t, newSQLite(t).CreateTable(context.Background(), []interface{}{&ddlTyped{}}))
+
+	// tag type takes precedence over the mapping of the dialect, unless it is a DataType
+	stmts, err = l.DDL(&ddlTyped{})
+	assert.NoError(t, err)
+	assert.EqualValues(t, []string{
+		"CREATE TABLE `ddl_typed` (`id` INTEGER NOT NULL,`note` MEDIUMTEXT,`code` VARCHAR(8),`updated_at` TIMESTAMP(6) DEFAULT CURRENT_TIMESTAMP(6),PRIMARY KEY (`id`),INDEX `idx_ddl_typed_note` (`note`))",
+	}, stmts)
+
+	_, err = l.DDL(&ddlBad{})
+	assert.True(t, errors.Is(err, ErrNoColumnType))
+}
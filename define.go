@@ -5,6 +5,8 @@ package layer
 import (
 	"database/sql"
 	"errors"
+
+	"github.com/meilihao/layer/dialect"
 )
 
 var (
@@ -12,6 +14,17 @@ var (
 	ErrNoRows = sql.ErrNoRows
 	ErrTxDone = sql.ErrTxDone
 
+	// driver errors translated by dialect, see DBError
+	ErrDuplicateKey         = dialect.ErrDuplicateKey
+	ErrForeignKeyViolation  = dialect.ErrForeignKeyViolation
+	ErrNotNullViolation     = dialect.ErrNotNullViolation
+	ErrCheckViolation       = dialect.ErrCheckViolation
+	ErrSerializationFailure = dialect.ErrSerializationFailure
+
+	ErrUnsupportedLocking  = dialect.ErrUnsupportedLocking
+	ErrUnsupportedCompound = dialect.ErrUnsupportedCompound
+	ErrUnsupportedFrame    = dialect.ErrUnsupportedFrame
+
 	// custom
 	ErrUnsupportedDriverName = errors.New("layer : Unsupported DriverName")
 	ErrEmptyDataSource       = errors.New("layer : Invalid DataSource")
@@ -20,4 +33,9 @@ var (
 	ErrUsingNonPtrModelData  = errors.New("layer : use non-ptr model data")
 	ErrUsingNilPtrModelData  = errors.New("layer : nil ptr model data")
 	ErrUsingNotStructModel   = errors.New("layer : not struct model")
+	ErrUsingNilMap           = errors.New("layer : nil map")
+	ErrArgWithoutColumn      = errors.New("layer : arg without column")
 )
+
+// DBError is a driver error classified by dialect, it carries the constraint name, table and column where the driver reports them
+type DBError = dialect.Error
@@ -105,7 +105,9 @@ func (se *DeleteSession) Delete(value interface{}) (interface{}, error) {
 	if se.err != nil {
 		return nil, se.err
 	}
-	se.value, _ = utils.PtrValue(value)
+	if se.value, _, se.err = utils.PtrValue(value); se.err != nil {
+		return nil, se.err
+	}
 	if se.table == "" {
 		se.table = se.schema.RawName
 	}
@@ -138,10 +140,10 @@ func (se *DeleteSession) Delete(value interface{}) (interface{}, error) {
 	se.builder = NewSQLBuilder(se.l, se.schema, 128)
 
 	if se.isUpdate {
-		se.clauses[clause.ClauseUpdate] = clause.Update{Table: clause.Table{Name: se.table}}
+		se.clauses[clause.ClauseUpdate] = clause.Update{Table: modelTable(se.schema, se.table)}
 		se.err = se.clauses.Build(se.builder, clause.ClauseUpdate, clause.ClauseSet, clause.ClauseWhere)
 	} else {
-		se.clauses[clause.ClauseDelete] = clause.Delete{Table: clause.Table{Name: se.table}}
+		se.clauses[clause.ClauseDelete] = clause.Delete{Table: modelTable(se.schema, se.table)}
 		se.err = se.clauses.Build(se.builder, clause.ClauseDelete, clause.ClauseWhere)
 	}
 
@@ -150,11 +152,9 @@ func (se *DeleteSession) Delete(value interface{}) (interface{}, error) {
 	}
 
 	now := time.Now()
-	if se.debug {
-		log.Info().Msgf("[%f] %s", time.Now().Sub(now).Seconds(), se.builder.String())
-	}
-
 	if se.dryRun {
+		se.l.logQuery(se.context, &QueryEvent{Op: OpDelete, Table: se.table, SQL: se.builder.String()}, se.debug)
+
 		return true, nil
 	}
 
@@ -166,9 +166,9 @@ func (se *DeleteSession) delete(now time.Time) (interface{}, error) {
 		se.context = context.Background()
 	}
 
-	stmt, err := se.l.db.PrepareContext(se.context, se.builder.String())
+	stmt, err := se.l.conn().PrepareContext(se.context, se.builder.String())
 	if err != nil {
-		return nil, err
+		return nil, se.l.TranslateError(err)
 	}
 
 	defer func() {
@@ -243,25 +243,24 @@ func (se *DeleteSession) delete1(s *sql.Stmt, v reflect.Value, now time.Time) (_
 		a = append(a, i)
 	}
 
-	if se.debug {
-		log.Info().Msg(se.l.dialecter.Explain(se.builder.String(), a))
+	ev := &QueryEvent{Op: OpDelete, Table: se.table, SQL: se.builder.String(), Args: a}
+	ctx := se.l.startQuery(se.context, ev)
+	r, err := s.ExecContext(ctx, a...)
+	if err == nil {
+		ev.RowsAffected, err = r.RowsAffected()
 	}
-
-	r, err := s.Exec(a...)
-	if err != nil {
-		return
-	}
-	n, err := r.RowsAffected()
-	if err != nil {
+	ev.Err = err
+	se.l.endQuery(ctx, ev, se.debug)
+	if err = ev.Err; err != nil {
 		return
 	}
-	if n == 0 {
+	if n := ev.RowsAffected; n == 0 {
 		return false, nil
 	} else if n == 1 {
 		return true, nil
 	}
 
-	return false, fmt.Errorf("huge: RowsAffected expected 0 or 1 but was %d", n)
+	return false, fmt.Errorf("huge: RowsAffected expected 0 or 1 but was %d", ev.RowsAffected)
 }
 
 // Delete T returns bool, []T returns map[int]struct{}, map[]T returns map[]struct{}.
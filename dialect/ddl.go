@@ -0,0 +1,63 @@
+// Copyright (c) 2017 github.com/meilihao. All rights reserved.
+
+package dialect
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DDLer extension of Dialecter for DDL, Dialecters of layer all implement it
+type DDLer interface {
+	// ColumnType type of column, dataType is schema.DataType or "json", "xml". returns "" for unknown dataType.
+	// size is the length of string and bytes, the bits of int and float, or the digits of decimal if precision > 0
+	ColumnType(dataType string, size, precision int, autoIncr bool) string
+	// AutoIncr constraint after the type of auto increment column, inlinePK is true if it contains PRIMARY KEY
+	AutoIncr() (constraint string, inlinePK bool)
+	// Comment how column comments are written
+	Comment() CommentStyle
+	// Literal quoted string literal of s, for DEFAULT and COMMENT
+	Literal(s string) string
+	// DefaultValue adjust DEFAULT expression v for the column of type typ
+	DefaultValue(typ, v string) string
+	// HasInlineIndex whether indexes are defined in CREATE TABLE, like `INDEX idx (col)` of mysql,
+	// otherwise they are created by CREATE INDEX [IF NOT EXISTS]
+	HasInlineIndex() bool
+}
+
+// CommentStyle how column comments are written
+type CommentStyle int
+
+const (
+	CommentUnsupported CommentStyle = iota
+	CommentInline                   // `col` INT COMMENT 'x'
+	CommentOn                       // COMMENT ON COLUMN "t"."col" IS 'x'
+)
+
+var (
+	_ DDLer = MySQLDialecter
+	_ DDLer = PostgresDialecter
+	_ DDLer = SQLiteDialecter
+)
+
+// intType picks the type of ints by bits
+func intType(size int, types [4]string) string {
+	switch {
+	case size <= 8:
+		return types[0]
+	case size <= 16:
+		return types[1]
+	case size <= 32:
+		return types[2]
+	}
+
+	return types[3]
+}
+
+func decimalType(name string, size, precision int) string {
+	return fmt.Sprintf("%s(%d,%d)", name, size, precision)
+}
+
+func sqlLiteral(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
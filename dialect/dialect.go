@@ -4,17 +4,62 @@ package dialect
 
 import (
 	"database/sql"
+	"errors"
+)
+
+var (
+	ErrUnsupportedLocking  = errors.New("layer : unsupported row locking")
+	ErrUnsupportedCompound = errors.New("layer : unsupported set operation")
+	ErrUnsupportedFrame    = errors.New("layer : unsupported window frame")
+)
+
+// locking strength and option of row locking clause
+const (
+	LockUpdate      = "UPDATE"
+	LockNoKeyUpdate = "NO KEY UPDATE"
+	LockShare       = "SHARE"
+	LockKeyShare    = "KEY SHARE"
+	LockNoWait      = "NOWAIT"
+	LockSkipLocked  = "SKIP LOCKED"
 )
 
 type Dialecter interface {
 	Dialect() string
+	// Queto quote a single identifier part, quote characters in it must be escaped
 	Queto(string) string
 	Arg(int) string
 	Returning(string) string
 	HasReturning() bool
 	Explain(sql string, vars []interface{}) string
+	// TranslateError classify driver error into *Error, unknown error is returned as is
+	TranslateError(err error) error
+	// Locking check row locking clause `FOR strength [OF tables] [option]`, returns ErrUnsupportedLocking if not supported
+	Locking(strength, option string, hasTables bool) error
+	// MultiTable syntax of UPDATE or DELETE(stmt) with other tables
+	MultiTable(stmt string) MultiTableStyle
+	// HasMutationLimit whether single-table UPDATE and DELETE support ORDER BY and LIMIT
+	HasMutationLimit() bool
+	// Compound check set operation op, like UNION ALL or INTERSECT, returns ErrUnsupportedCompound if not supported
+	Compound(op string) error
+	// HasNestedCompound whether members of set operations can be parenthesized
+	HasNestedCompound() bool
+	// Frame check unit of window frame, like ROWS or GROUPS, returns ErrUnsupportedFrame if not supported
+	Frame(typ string) error
+	// Func template of function name with nargs args, see commonFuncs for its format. ok is false if it has no template
+	Func(name string, nargs int) (tmpl string, ok bool)
+	// JSONPath path arg of JSON_* functions, keys are object keys(string) or array indexes(int)
+	JSONPath(keys []interface{}) string
 }
 
+// MultiTableStyle syntax of UPDATE and DELETE with other tables
+type MultiTableStyle int
+
+const (
+	MultiTableUnsupported MultiTableStyle = iota
+	MultiTableJoin                        // UPDATE a JOIN b ON ... SET ...; DELETE a FROM a JOIN b ON ...
+	MultiTableFrom                        // UPDATE a SET ... FROM b WHERE ...; DELETE FROM a USING b WHERE ...
+)
+
 // NewDialecter init a Dialecter
 // inject db for Dialecter's Extension methods
 func NewDialecter(dialect string, db *sql.DB) Dialecter {
@@ -4,6 +4,9 @@ package dialect
 
 import (
 	"database/sql"
+	"fmt"
+	"regexp"
+	"strings"
 )
 
 type MySQL struct {
@@ -20,7 +23,7 @@ func (MySQL) Dialect() string {
 }
 
 func (MySQL) Queto(s string) string {
-	return "`" + s + "`"
+	return "`" + strings.ReplaceAll(s, "`", "``") + "`"
 }
 
 func (MySQL) Arg(i int) string {
@@ -38,3 +41,157 @@ func (MySQL) HasReturning() bool {
 func (MySQL) Explain(sql string, vars []interface{}) string {
 	return ExplainSQL(sql, nil, `'`, vars)
 }
+
+func (MySQL) TranslateError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return translateMySQLError(err)
+}
+
+// Locking FOR SHARE, OF, NOWAIT and SKIP LOCKED need MySQL 8.0
+func (MySQL) Locking(strength, option string, hasTables bool) error {
+	if strength != LockUpdate && strength != LockShare {
+		return fmt.Errorf("%w: FOR %s in mysql", ErrUnsupportedLocking, strength)
+	}
+
+	return nil
+}
+
+func (MySQL) MultiTable(stmt string) MultiTableStyle {
+	return MultiTableJoin
+}
+
+func (MySQL) HasMutationLimit() bool {
+	return true
+}
+
+// Compound INTERSECT and EXCEPT need mysql 8.0.31+
+func (MySQL) Compound(op string) error {
+	return nil
+}
+
+func (MySQL) HasNestedCompound() bool {
+	return true
+}
+
+// Frame mysql has no GROUPS frame
+func (MySQL) Frame(typ string) error {
+	if typ == "GROUPS" {
+		return fmt.Errorf("%w: %s in mysql", ErrUnsupportedFrame, typ)
+	}
+
+	return nil
+}
+
+var mysqlFuncs = map[string]string{
+	"CAST_INT":            "CAST({0} AS SIGNED)",
+	"CAST_FLOAT":          "CAST({0} AS DOUBLE)",
+	"CAST_TEXT":           "CAST({0} AS CHAR)",
+	"CAST_DATETIME":       "CAST({0} AS DATETIME)",
+	"DATE_TRUNC_YEAR":     "CAST(DATE_FORMAT({0},'%Y-01-01') AS DATETIME)",
+	"DATE_TRUNC_MONTH":    "CAST(DATE_FORMAT({0},'%Y-%m-01') AS DATETIME)",
+	"DATE_TRUNC_DAY":      "CAST(DATE({0}) AS DATETIME)",
+	"DATE_TRUNC_HOUR":     "CAST(DATE_FORMAT({0},'%Y-%m-%d %H:00:00') AS DATETIME)",
+	"DATE_TRUNC_MINUTE":   "CAST(DATE_FORMAT({0},'%Y-%m-%d %H:%i:00') AS DATETIME)",
+	"JSON_EXTRACT":        "JSON_EXTRACT({0},{1})",
+	"JSON_EXTRACT_TEXT":   "JSON_UNQUOTE(JSON_EXTRACT({0},{1}))",
+	"JSON_CONTAINS":       "JSON_CONTAINS({0},{1})",
+	"JSON_HAS_KEY":        "JSON_CONTAINS_PATH({0},'one',{1})",
+	"JSON_ARRAY_CONTAINS": "JSON_CONTAINS({0},{2},{1})",
+	"JSON_SET":            "JSON_SET({0},{1},CAST({2} AS JSON))",
+}
+
+func init() {
+	for _, u := range dateUnits {
+		mysqlFuncs["DATE_ADD_"+u] = "DATE_ADD({0},INTERVAL {1} " + u + ")"
+		mysqlFuncs["DATE_SUB_"+u] = "DATE_SUB({0},INTERVAL {1} " + u + ")"
+	}
+}
+
+func (MySQL) Func(name string, nargs int) (string, bool) {
+	return funcTemplate(mysqlFuncs, name, nargs)
+}
+
+func (MySQL) JSONPath(keys []interface{}) string {
+	return jsonPath(keys)
+}
+
+func (MySQL) ColumnType(dataType string, size, precision int, autoIncr bool) string {
+	switch dataType {
+	case "bool":
+		return "BOOLEAN"
+	case "int":
+		return intType(size, [4]string{"TINYINT", "SMALLINT", "INT", "BIGINT"})
+	case "uint":
+		return intType(size, [4]string{"TINYINT", "SMALLINT", "INT", "BIGINT"}) + " UNSIGNED"
+	case "float":
+		if precision > 0 {
+			return decimalType("DECIMAL", size, precision)
+		}
+		if size <= 32 {
+			return "FLOAT"
+		}
+		return "DOUBLE"
+	case "string":
+		if size <= 0 {
+			return "VARCHAR(255)"
+		}
+		// max row size is 65535 bytes, 16383 characters of utf8mb4
+		if size > 16383 {
+			return "LONGTEXT"
+		}
+		return fmt.Sprintf("VARCHAR(%d)", size)
+	case "time":
+		return "DATETIME(3)"
+	case "bytes":
+		if size <= 0 || size > 65535 {
+			return "LONGBLOB"
+		}
+		return fmt.Sprintf("VARBINARY(%d)", size)
+	case "json":
+		return "JSON"
+	case "xml":
+		return "LONGTEXT"
+	}
+
+	return ""
+}
+
+func (MySQL) AutoIncr() (string, bool) {
+	return "AUTO_INCREMENT", false
+}
+
+func (MySQL) Comment() CommentStyle {
+	return CommentInline
+}
+
+// DefaultValue CURRENT_TIMESTAMP takes the fractional seconds precision of DATETIME(n) and TIMESTAMP(n),
+// otherwise mysql rejects it by error 1067
+func (MySQL) DefaultValue(typ, v string) string {
+	if !mysqlNow.MatchString(v) {
+		return v
+	}
+
+	if m := mysqlFsp.FindStringSubmatch(typ); m != nil {
+		return "CURRENT_TIMESTAMP(" + m[1] + ")"
+	}
+
+	return "CURRENT_TIMESTAMP"
+}
+
+var (
+	mysqlNow = regexp.MustCompile(`(?i)^\s*(CURRENT_TIMESTAMP|NOW|LOCALTIME|LOCALTIMESTAMP)\s*(\(\s*\d*\s*\))?\s*$`)
+	mysqlFsp = regexp.MustCompile(`(?i)^\s*(?:DATETIME|TIMESTAMP)\s*\(\s*(\d+)\s*\)`)
+)
+
+// HasInlineIndex mysql has no CREATE INDEX IF NOT EXISTS, so indexes are in CREATE TABLE [IF NOT EXISTS]
+func (MySQL) HasInlineIndex() bool {
+	return true
+}
+
+// Literal backslash is an escape character in mysql string literal by default
+func (MySQL) Literal(s string) string {
+	return sqlLiteral(strings.ReplaceAll(s, `\`, `\\`))
+}
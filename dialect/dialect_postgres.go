@@ -4,8 +4,10 @@ package dialect
 
 import (
 	"database/sql"
+	"fmt"
 	"regexp"
 	"strconv"
+	"strings"
 )
 
 type Postgres struct {
@@ -22,7 +24,7 @@ func (Postgres) Dialect() string {
 }
 
 func (Postgres) Queto(s string) string {
-	return `"` + s + `"`
+	return `"` + strings.ReplaceAll(s, `"`, `""`) + `"`
 }
 
 func (Postgres) Arg(i int) string {
@@ -42,3 +44,148 @@ var numericPlaceholder = regexp.MustCompile("\\$(\\d+)")
 func (Postgres) Explain(sql string, vars []interface{}) string {
 	return ExplainSQL(sql, numericPlaceholder, `'`, vars)
 }
+
+func (Postgres) TranslateError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return translatePostgresError(err)
+}
+
+func (Postgres) Locking(strength, option string, hasTables bool) error {
+	switch strength {
+	case LockUpdate, LockNoKeyUpdate, LockShare, LockKeyShare:
+		return nil
+	}
+
+	return fmt.Errorf("%w: FOR %s in postgres", ErrUnsupportedLocking, strength)
+}
+
+func (Postgres) MultiTable(stmt string) MultiTableStyle {
+	return MultiTableFrom
+}
+
+func (Postgres) HasMutationLimit() bool {
+	return false
+}
+
+func (Postgres) Compound(op string) error {
+	return nil
+}
+
+func (Postgres) HasNestedCompound() bool {
+	return true
+}
+
+func (Postgres) Frame(typ string) error {
+	return nil
+}
+
+var postgresFuncs = map[string]string{
+	"SUBSTRING/2":         "SUBSTRING({0} FROM {1})",
+	"SUBSTRING/3":         "SUBSTRING({0} FROM {1} FOR {2})",
+	"ROUND":               "ROUND(CAST({0} AS NUMERIC),{1})", // ROUND(double precision, int) does not exist
+	"CAST_INT":            "CAST({0} AS BIGINT)",
+	"CAST_FLOAT":          "CAST({0} AS DOUBLE PRECISION)",
+	"CAST_TEXT":           "CAST({0} AS TEXT)",
+	"CAST_DATETIME":       "CAST({0} AS TIMESTAMP)",
+	"JSON_EXTRACT":        "({0} #> CAST({1} AS TEXT[]))",
+	"JSON_EXTRACT_TEXT":   "({0} #>> CAST({1} AS TEXT[]))",
+	"JSON_CONTAINS":       "({0} @> CAST({1} AS JSONB))",
+	"JSON_HAS_KEY":        "(({0} #> CAST({1} AS TEXT[])) IS NOT NULL)",
+	"JSON_ARRAY_CONTAINS": "(({0} #> CAST({1} AS TEXT[])) @> CAST({2} AS JSONB))",
+	"JSON_SET":            "JSONB_SET({0},CAST({1} AS TEXT[]),CAST({2} AS JSONB))",
+}
+
+func init() {
+	args := map[string]string{"SECOND": "secs", "MINUTE": "mins", "HOUR": "hours", "DAY": "days", "MONTH": "months", "YEAR": "years"}
+
+	for _, u := range dateUnits {
+		postgresFuncs["DATE_ADD_"+u] = "({0} + MAKE_INTERVAL(" + args[u] + " => {1}))"
+		postgresFuncs["DATE_SUB_"+u] = "({0} - MAKE_INTERVAL(" + args[u] + " => {1}))"
+		postgresFuncs["DATE_TRUNC_"+u] = "DATE_TRUNC('" + strings.ToLower(u) + "',{0})"
+	}
+}
+
+func (Postgres) Func(name string, nargs int) (string, bool) {
+	return funcTemplate(postgresFuncs, name, nargs)
+}
+
+// JSONPath text array like {a,"b c",0}, it is the path of #>, #>> and jsonb_set
+func (Postgres) JSONPath(keys []interface{}) string {
+	var b strings.Builder
+
+	b.WriteByte('{')
+	for idx, k := range keys {
+		if idx > 0 {
+			b.WriteByte(',')
+		}
+
+		b.WriteString(`"` + jsonKeyEscaper.Replace(toString(k)) + `"`)
+	}
+	b.WriteByte('}')
+
+	return b.String()
+}
+
+func (Postgres) ColumnType(dataType string, size, precision int, autoIncr bool) string {
+	switch dataType {
+	case "bool":
+		return "BOOLEAN"
+	case "int", "uint":
+		// no unsigned types, uint takes the next larger type
+		if dataType == "uint" {
+			size *= 2
+		}
+		if autoIncr {
+			return intType(size, [4]string{"SMALLSERIAL", "SMALLSERIAL", "SERIAL", "BIGSERIAL"})
+		}
+		return intType(size, [4]string{"SMALLINT", "SMALLINT", "INTEGER", "BIGINT"})
+	case "float":
+		if precision > 0 {
+			return decimalType("NUMERIC", size, precision)
+		}
+		if size <= 32 {
+			return "REAL"
+		}
+		return "DOUBLE PRECISION"
+	case "string":
+		if size <= 0 {
+			return "TEXT"
+		}
+		return "VARCHAR(" + strconv.Itoa(size) + ")"
+	case "time":
+		return "TIMESTAMPTZ"
+	case "bytes":
+		return "BYTEA"
+	case "json":
+		// JSON functions of postgres use jsonb
+		return "JSONB"
+	case "xml":
+		return "XML"
+	}
+
+	return ""
+}
+
+// AutoIncr serial types are used instead
+func (Postgres) AutoIncr() (string, bool) {
+	return "", false
+}
+
+func (Postgres) Comment() CommentStyle {
+	return CommentOn
+}
+
+func (Postgres) Literal(s string) string {
+	return sqlLiteral(s)
+}
+
+func (Postgres) DefaultValue(typ, v string) string {
+	return v
+}
+
+func (Postgres) HasInlineIndex() bool {
+	return false
+}
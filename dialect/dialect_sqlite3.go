@@ -4,6 +4,9 @@ package dialect
 
 import (
 	"database/sql"
+	"fmt"
+	"regexp"
+	"strings"
 )
 
 type SQLite struct {
@@ -20,7 +23,7 @@ func (SQLite) Dialect() string {
 }
 
 func (SQLite) Queto(s string) string {
-	return "\"" + s + "\""
+	return "\"" + strings.ReplaceAll(s, "\"", `""`) + "\""
 }
 
 func (SQLite) Arg(i int) string {
@@ -38,3 +41,144 @@ func (SQLite) HasReturning() bool {
 func (SQLite) Explain(sql string, vars []interface{}) string {
 	return ExplainSQL(sql, nil, `"`, vars)
 }
+
+func (SQLite) TranslateError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return translateSQLiteError(err)
+}
+
+// Locking sqlite locks the whole database in a write transaction, it has no row locking clause
+func (SQLite) Locking(strength, option string, hasTables bool) error {
+	return fmt.Errorf("%w: FOR %s in sqlite3", ErrUnsupportedLocking, strength)
+}
+
+// MultiTable UPDATE FROM needs sqlite 3.33, DELETE can only use sub query
+func (SQLite) MultiTable(stmt string) MultiTableStyle {
+	if stmt == "UPDATE" {
+		return MultiTableFrom
+	}
+
+	return MultiTableUnsupported
+}
+
+// HasMutationLimit sqlite needs SQLITE_ENABLE_UPDATE_DELETE_LIMIT
+func (SQLite) HasMutationLimit() bool {
+	return false
+}
+
+// Compound sqlite has no ALL of INTERSECT and EXCEPT, and no UNION DISTINCT
+func (SQLite) Compound(op string) error {
+	switch op {
+	case "UNION", "UNION ALL", "INTERSECT", "EXCEPT":
+		return nil
+	}
+
+	return fmt.Errorf("%w: %s in sqlite3", ErrUnsupportedCompound, op)
+}
+
+// HasNestedCompound members are not parenthesized and all set operations are left-associative
+func (SQLite) HasNestedCompound() bool {
+	return false
+}
+
+// Frame GROUPS needs sqlite 3.28
+func (SQLite) Frame(typ string) error {
+	return nil
+}
+
+var sqliteFuncs = map[string]string{
+	"CONCAT":              "({* || })",
+	"LENGTH":              "LENGTH({0})",
+	"SUBSTRING/2":         "SUBSTR({0},{1})",
+	"SUBSTRING/3":         "SUBSTR({0},{1},{2})",
+	"CAST_INT":            "CAST({0} AS INTEGER)",
+	"CAST_FLOAT":          "CAST({0} AS REAL)",
+	"CAST_TEXT":           "CAST({0} AS TEXT)",
+	"CAST_DATE":           "DATE({0})",
+	"CAST_DATETIME":       "DATETIME({0})",
+	"DATE_TRUNC_YEAR":     "DATETIME({0},'start of year')",
+	"DATE_TRUNC_MONTH":    "DATETIME({0},'start of month')",
+	"DATE_TRUNC_DAY":      "DATETIME({0},'start of day')",
+	"DATE_TRUNC_HOUR":     "STRFTIME('%Y-%m-%d %H:00:00',{0})",
+	"DATE_TRUNC_MINUTE":   "STRFTIME('%Y-%m-%d %H:%M:00',{0})",
+	"JSON_EXTRACT":        "JSON_EXTRACT({0},{1})",
+	"JSON_EXTRACT_TEXT":   "JSON_EXTRACT({0},{1})",
+	"JSON_CONTAINS":       "",
+	"JSON_HAS_KEY":        "(JSON_TYPE({0},{1}) IS NOT NULL)",
+	"JSON_ARRAY_CONTAINS": "EXISTS(SELECT 1 FROM JSON_EACH({0},{1}) WHERE JSON_EACH.value = JSON_EXTRACT({2},'$[0]'))",
+	"JSON_SET":            "JSON_SET({0},{1},JSON({2}))",
+}
+
+func init() {
+	for _, u := range dateUnits {
+		sqliteFuncs["DATE_ADD_"+u] = "DATETIME({0},{1} || ' " + strings.ToLower(u) + "s')"
+		sqliteFuncs["DATE_SUB_"+u] = "DATETIME({0},'-' || {1} || ' " + strings.ToLower(u) + "s')"
+	}
+}
+
+func (SQLite) Func(name string, nargs int) (string, bool) {
+	return funcTemplate(sqliteFuncs, name, nargs)
+}
+
+func (SQLite) JSONPath(keys []interface{}) string {
+	return jsonPath(keys)
+}
+
+func (SQLite) ColumnType(dataType string, size, precision int, autoIncr bool) string {
+	switch dataType {
+	case "bool":
+		return "BOOLEAN"
+	case "int", "uint":
+		// AUTOINCREMENT is only allowed on INTEGER PRIMARY KEY
+		return "INTEGER"
+	case "float":
+		if precision > 0 {
+			return decimalType("NUMERIC", size, precision)
+		}
+		return "REAL"
+	case "string":
+		if size <= 0 {
+			return "TEXT"
+		}
+		return fmt.Sprintf("VARCHAR(%d)", size)
+	case "time":
+		return "DATETIME"
+	case "bytes":
+		return "BLOB"
+	case "json", "xml":
+		return "TEXT"
+	}
+
+	return ""
+}
+
+func (SQLite) AutoIncr() (string, bool) {
+	return "PRIMARY KEY AUTOINCREMENT", true
+}
+
+// Comment sqlite has no column comment
+func (SQLite) Comment() CommentStyle {
+	return CommentUnsupported
+}
+
+func (SQLite) Literal(s string) string {
+	return sqlLiteral(s)
+}
+
+// DefaultValue sqlite needs parentheses around expressions, only literals, NULL and CURRENT_* are written as is
+func (SQLite) DefaultValue(typ, v string) string {
+	if sqliteLiteral.MatchString(v) || isWrapped(strings.TrimSpace(v)) {
+		return v
+	}
+
+	return "(" + v + ")"
+}
+
+var sqliteLiteral = regexp.MustCompile(`(?i)^\s*('([^']|'')*'|[xX]'[0-9a-fA-F]*'|[+-]?(\d+(\.\d*)?|\.\d+)([eE][+-]?\d+)?|NULL|TRUE|FALSE|CURRENT_TIME|CURRENT_DATE|CURRENT_TIMESTAMP)\s*$`)
+
+func (SQLite) HasInlineIndex() bool {
+	return false
+}
@@ -0,0 +1,234 @@
+// Copyright (c) 2017 github.com/meilihao. All rights reserved.
+
+package dialect
+
+import (
+	"errors"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// classified driver errors, use errors.Is(err, ErrXXX) to check them
+var (
+	ErrDuplicateKey         = errors.New("duplicate key")
+	ErrForeignKeyViolation  = errors.New("foreign key violation")
+	ErrNotNullViolation     = errors.New("not null violation")
+	ErrCheckViolation       = errors.New("check violation")
+	ErrSerializationFailure = errors.New("serialization failure")
+)
+
+// Error is a driver error classified by Dialecter.TranslateError,
+// Constraint, Table and Column are filled where the driver reports them.
+type Error struct {
+	Kind       error
+	Constraint string
+	Table      string
+	Column     string
+	Err        error // raw driver error
+}
+
+func (e *Error) Error() string {
+	var b strings.Builder
+
+	b.WriteString("layer : ")
+	b.WriteString(e.Kind.Error())
+
+	for _, kv := range [][2]string{{"constraint", e.Constraint}, {"table", e.Table}, {"column", e.Column}} {
+		if kv[1] != "" {
+			b.WriteString(" " + kv[0] + "=" + kv[1])
+		}
+	}
+
+	if e.Err != nil {
+		b.WriteString(": ")
+		b.WriteString(e.Err.Error())
+	}
+
+	return b.String()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+func (e *Error) Is(target error) bool {
+	return e.Kind == target
+}
+
+// errField find the first error in err's chain which is a struct(or pointer to struct) has one of names field.
+// it is used to read driver errors without importing drivers.
+func errField(err error, names ...string) (reflect.Value, bool) {
+	for ; err != nil; err = errors.Unwrap(err) {
+		v := reflect.ValueOf(err)
+		for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+			if v.IsNil() {
+				break
+			}
+			v = v.Elem()
+		}
+
+		if v.Kind() != reflect.Struct {
+			continue
+		}
+
+		for _, name := range names {
+			if f := v.FieldByName(name); f.IsValid() && f.CanInterface() {
+				return f, true
+			}
+		}
+	}
+
+	return reflect.Value{}, false
+}
+
+func errString(err error, names ...string) string {
+	if f, ok := errField(err, names...); ok && f.Kind() == reflect.String {
+		return f.String()
+	}
+
+	return ""
+}
+
+func errInt(err error, names ...string) (int64, bool) {
+	if f, ok := errField(err, names...); ok {
+		switch f.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return f.Int(), true
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			return int64(f.Uint()), true
+		}
+	}
+
+	return 0, false
+}
+
+// sqlState get SQLSTATE from pgconn.PgError, pq.Error or other errors with `SQLState() string`/`Code string`
+func sqlState(err error) string {
+	for e := err; e != nil; e = errors.Unwrap(e) {
+		if s, ok := e.(interface{ SQLState() string }); ok {
+			return s.SQLState()
+		}
+	}
+
+	return errString(err, "Code")
+}
+
+var (
+	mysqlDupKeyRe   = regexp.MustCompile("for key '([^']+)'")
+	mysqlFKRe       = regexp.MustCompile("\\(`(?:[^`]+`\\.`)?([^`]+)`, CONSTRAINT `([^`]+)` FOREIGN KEY \\(`([^`]+)`\\)")
+	mysqlColumnRe   = regexp.MustCompile("Column '([^']+)'")
+	mysqlFieldRe    = regexp.MustCompile("Field '([^']+)'")
+	mysqlCheckRe    = regexp.MustCompile("Check constraint '([^']+)'")
+	pgDetailKeyRe   = regexp.MustCompile(`Key \(([^)]+)\)=`)
+	sqliteFailedRe  = regexp.MustCompile(`(UNIQUE|NOT NULL|CHECK|FOREIGN KEY) constraint failed(?:: (.+))?`)
+	sqliteColumnsRe = regexp.MustCompile(`^([^.\s,]+)\.([^\s,]+)`)
+)
+
+// translateMySQLError classify go-sql-driver/mysql MySQLError{Number, Message}
+func translateMySQLError(err error) error {
+	n, ok := errInt(err, "Number")
+	if !ok {
+		return err
+	}
+	msg := errString(err, "Message")
+
+	e := &Error{Err: err}
+	switch n {
+	case 1062, 1586: // ER_DUP_ENTRY, ER_DUP_ENTRY_WITH_KEY_NAME
+		e.Kind = ErrDuplicateKey
+		if m := mysqlDupKeyRe.FindStringSubmatch(msg); m != nil {
+			// 8.0 reports `table.key`
+			if ns := strings.SplitN(m[1], ".", 2); len(ns) == 2 {
+				e.Table, e.Constraint = ns[0], ns[1]
+			} else {
+				e.Constraint = m[1]
+			}
+		}
+	case 1216, 1217, 1451, 1452: // ER_NO_REFERENCED_ROW(_2), ER_ROW_IS_REFERENCED(_2)
+		e.Kind = ErrForeignKeyViolation
+		if m := mysqlFKRe.FindStringSubmatch(msg); m != nil {
+			e.Table, e.Constraint, e.Column = m[1], m[2], m[3]
+		}
+	case 1048, 1364: // ER_BAD_NULL_ERROR, ER_NO_DEFAULT_FOR_FIELD
+		e.Kind = ErrNotNullViolation
+		if m := mysqlColumnRe.FindStringSubmatch(msg); m != nil {
+			e.Column = m[1]
+		} else if m := mysqlFieldRe.FindStringSubmatch(msg); m != nil {
+			e.Column = m[1]
+		}
+	case 3819: // ER_CHECK_CONSTRAINT_VIOLATED
+		e.Kind = ErrCheckViolation
+		if m := mysqlCheckRe.FindStringSubmatch(msg); m != nil {
+			e.Constraint = m[1]
+		}
+	case 1213: // ER_LOCK_DEADLOCK
+		e.Kind = ErrSerializationFailure
+	default:
+		return err
+	}
+
+	return e
+}
+
+// translatePostgresError classify errors by SQLSTATE, support lib/pq and jackc/pgx
+func translatePostgresError(err error) error {
+	e := &Error{Err: err}
+
+	switch sqlState(err) {
+	case "23505":
+		e.Kind = ErrDuplicateKey
+	case "23503":
+		e.Kind = ErrForeignKeyViolation
+	case "23502":
+		e.Kind = ErrNotNullViolation
+	case "23514":
+		e.Kind = ErrCheckViolation
+	case "40001", "40P01":
+		e.Kind = ErrSerializationFailure
+	default:
+		return err
+	}
+
+	// pgconn.PgError uses XXXName, pq.Error not
+	e.Constraint = errString(err, "ConstraintName", "Constraint")
+	e.Table = errString(err, "TableName", "Table")
+	e.Column = errString(err, "ColumnName", "Column")
+
+	if e.Column == "" {
+		if m := pgDetailKeyRe.FindStringSubmatch(errString(err, "Detail")); m != nil {
+			e.Column = m[1]
+		}
+	}
+
+	return e
+}
+
+// translateSQLiteError classify sqlite errors by message, like "UNIQUE constraint failed: users.email"
+func translateSQLiteError(err error) error {
+	m := sqliteFailedRe.FindStringSubmatch(err.Error())
+	if m == nil {
+		return err
+	}
+
+	e := &Error{Err: err}
+	switch m[1] {
+	case "UNIQUE":
+		e.Kind = ErrDuplicateKey
+	case "NOT NULL":
+		e.Kind = ErrNotNullViolation
+	case "FOREIGN KEY":
+		e.Kind = ErrForeignKeyViolation
+	case "CHECK":
+		e.Kind = ErrCheckViolation
+		e.Constraint = m[2]
+
+		return e
+	}
+
+	if c := sqliteColumnsRe.FindStringSubmatch(m[2]); c != nil {
+		e.Table, e.Column = c[1], c[2]
+	}
+
+	return e
+}
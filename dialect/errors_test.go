@@ -0,0 +1,95 @@
+package dialect
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// same shape as github.com/go-sql-driver/mysql.MySQLError
+type mysqlError struct {
+	Number  uint16
+	Message string
+}
+
+func (e *mysqlError) Error() string { return fmt.Sprintf("Error %d: %s", e.Number, e.Message) }
+
+// same shape as github.com/lib/pq.Error
+type pqErrorCode string
+
+type pqError struct {
+	Code       pqErrorCode
+	Message    string
+	Detail     string
+	Table      string
+	Column     string
+	Constraint string
+}
+
+func (e *pqError) Error() string { return "pq: " + e.Message }
+
+// same shape as github.com/jackc/pgconn.PgError
+type pgError struct {
+	Code           string
+	Message        string
+	ConstraintName string
+	TableName      string
+}
+
+func (e *pgError) Error() string    { return e.Message }
+func (e *pgError) SQLState() string { return e.Code }
+
+func TestTranslateError(t *testing.T) {
+	cases := []struct {
+		d          Dialecter
+		err        error
+		kind       error
+		constraint string
+		table      string
+		column     string
+	}{
+		{MySQLDialecter, &mysqlError{1062, "Duplicate entry 'a@b.c' for key 'users.email'"}, ErrDuplicateKey, "email", "users", ""},
+		{MySQLDialecter, &mysqlError{1062, "Duplicate entry '1' for key 'PRIMARY'"}, ErrDuplicateKey, "PRIMARY", "", ""},
+		{MySQLDialecter, &mysqlError{1452, "Cannot add or update a child row: a foreign key constraint fails (`db`.`orders`, CONSTRAINT `fk_user` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`))"},
+			ErrForeignKeyViolation, "fk_user", "orders", "user_id"},
+		{MySQLDialecter, &mysqlError{1048, "Column 'name' cannot be null"}, ErrNotNullViolation, "", "", "name"},
+		{MySQLDialecter, &mysqlError{3819, "Check constraint 'chk_age' is violated."}, ErrCheckViolation, "chk_age", "", ""},
+		{MySQLDialecter, &mysqlError{1213, "Deadlock found when trying to get lock"}, ErrSerializationFailure, "", "", ""},
+		{PostgresDialecter, &pqError{Code: "23505", Detail: "Key (email)=(a@b.c) already exists.", Table: "users", Constraint: "users_email_key"},
+			ErrDuplicateKey, "users_email_key", "users", "email"},
+		{PostgresDialecter, &pqError{Code: "23502", Table: "users", Column: "name"}, ErrNotNullViolation, "", "users", "name"},
+		{PostgresDialecter, fmt.Errorf("wrap: %w", &pgError{Code: "23503", ConstraintName: "fk_user", TableName: "orders"}),
+			ErrForeignKeyViolation, "fk_user", "orders", ""},
+		{PostgresDialecter, &pgError{Code: "40001"}, ErrSerializationFailure, "", "", ""},
+		{PostgresDialecter, &pgError{Code: "23514", ConstraintName: "chk_age"}, ErrCheckViolation, "chk_age", "", ""},
+		{SQLiteDialecter, errors.New("UNIQUE constraint failed: users.email"), ErrDuplicateKey, "", "users", "email"},
+		{SQLiteDialecter, errors.New("NOT NULL constraint failed: users.name"), ErrNotNullViolation, "", "users", "name"},
+		{SQLiteDialecter, errors.New("FOREIGN KEY constraint failed"), ErrForeignKeyViolation, "", "", ""},
+		{SQLiteDialecter, errors.New("CHECK constraint failed: chk_age"), ErrCheckViolation, "chk_age", "", ""},
+	}
+
+	for idx, c := range cases {
+		t.Run(fmt.Sprintf("case #%v", idx), func(t *testing.T) {
+			err := c.d.TranslateError(c.err)
+			assert.True(t, errors.Is(err, c.kind), err)
+
+			var e *Error
+			assert.True(t, errors.As(err, &e))
+			assert.EqualValues(t, c.constraint, e.Constraint)
+			assert.EqualValues(t, c.table, e.Table)
+			assert.EqualValues(t, c.column, e.Column)
+			assert.True(t, errors.Is(err, c.err))
+		})
+	}
+
+	// unknown errors are returned as is
+	raw := errors.New("connection refused")
+	for _, d := range []Dialecter{MySQLDialecter, PostgresDialecter, SQLiteDialecter} {
+		assert.EqualValues(t, raw, d.TranslateError(raw))
+		assert.Nil(t, d.TranslateError(nil))
+	}
+	mysqlRaw := &mysqlError{1045, "Access denied"}
+	assert.EqualValues(t, mysqlRaw, MySQLDialecter.TranslateError(mysqlRaw))
+}
@@ -0,0 +1,74 @@
+package dialect
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// function templates used by Dialecter.Func, {N} is the N-th arg, {*} is all args joined by ','
+// and {*sep} is all args joined by sep. key is NAME or NAME/nargs, the latter takes precedence.
+// functions without template are written as NAME(args), an empty template means the dialect does not support it.
+var commonFuncs = map[string]string{
+	"COUNT_DISTINCT": "COUNT(DISTINCT {0})",
+	"NOW":            "CURRENT_TIMESTAMP",
+	"CONCAT":         "CONCAT({*})",
+	"LENGTH":         "CHAR_LENGTH({0})",
+	"SUBSTRING/2":    "SUBSTRING({0},{1})",
+	"SUBSTRING/3":    "SUBSTRING({0},{1},{2})",
+	"CAST_DATE":      "CAST({0} AS DATE)",
+}
+
+// date units of DATE_ADD_*, DATE_SUB_* and DATE_TRUNC_*
+var dateUnits = []string{"SECOND", "MINUTE", "HOUR", "DAY", "MONTH", "YEAR"}
+
+func funcTemplate(funcs map[string]string, name string, nargs int) (string, bool) {
+	key := name + "/" + strconv.Itoa(nargs)
+
+	for _, m := range []map[string]string{funcs, commonFuncs} {
+		if t, ok := m[key]; ok {
+			return t, true
+		}
+		if t, ok := m[name]; ok {
+			return t, true
+		}
+	}
+
+	return "", false
+}
+
+var (
+	jsonIdent      = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+	jsonKeyEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+)
+
+// jsonPath path like $.a."b c"[0] used by mysql and sqlite
+func jsonPath(keys []interface{}) string {
+	var b strings.Builder
+
+	b.WriteByte('$')
+	for _, k := range keys {
+		switch v := k.(type) {
+		case int:
+			b.WriteString("[" + strconv.Itoa(v) + "]")
+		default:
+			s := toString(v)
+			if jsonIdent.MatchString(s) {
+				b.WriteString("." + s)
+			} else {
+				b.WriteString(".\"" + jsonKeyEscaper.Replace(s) + "\"")
+			}
+		}
+	}
+
+	return b.String()
+}
+
+func toString(v interface{}) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+
+	return fmt.Sprint(v)
+}
@@ -0,0 +1,537 @@
+// Copyright (c) 2017 github.com/meilihao. All rights reserved.
+
+package dialect
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"regexp"
+	"strings"
+)
+
+var (
+	ErrNoDB = errors.New("layer : dialect has no db for introspection")
+)
+
+// Migrator extension of Dialecter for migration, it reads tables by the db injected by NewDialecter
+type Migrator interface {
+	DDLer
+	// Table read table name of schema(empty is the current one), returns nil if the table does not exist
+	Table(ctx context.Context, schema, name string) (*TableInfo, error)
+	// NormalizeType canonical form of column type, types with the same form are the same type
+	NormalizeType(typ string) string
+	// NormalizeDefault canonical form of DEFAULT v read from db or written by DDL, string literals are unquoted
+	NormalizeDefault(v string) string
+	// Alter syntax of ALTER TABLE
+	Alter() AlterStyle
+}
+
+// AlterStyle syntax of changing columns, indexes and constraints
+type AlterStyle int
+
+const (
+	AlterLimited  AlterStyle = iota // only ADD COLUMN, DROP COLUMN, CREATE INDEX and DROP INDEX name
+	AlterModify                     // MODIFY COLUMN, DROP FOREIGN KEY and DROP INDEX name ON table
+	AlterStandard                   // ALTER COLUMN TYPE/SET NOT NULL, DROP CONSTRAINT and DROP INDEX name
+)
+
+// TableInfo table read from db, names are db names
+type TableInfo struct {
+	Schema      string
+	Name        string
+	Columns     []*ColumnInfo
+	PrimaryKey  []string
+	Indexes     []*IndexInfo // without the primary key
+	ForeignKeys []*ForeignKeyInfo
+}
+
+// Column column by name, case insensitive
+func (t *TableInfo) Column(name string) *ColumnInfo {
+	for _, c := range t.Columns {
+		if strings.EqualFold(c.Name, name) {
+			return c
+		}
+	}
+
+	return nil
+}
+
+type ColumnInfo struct {
+	Name    string
+	Type    string
+	NotNull bool
+	Default sql.NullString
+	Comment string
+}
+
+type IndexInfo struct {
+	Name       string
+	Unique     bool
+	Constraint bool // index of UNIQUE constraint, postgres drops it by DROP CONSTRAINT and sqlite can not drop it
+	Columns    []string
+}
+
+type ForeignKeyInfo struct {
+	Name       string // empty in sqlite
+	Columns    []string
+	RefTable   string
+	RefColumns []string
+}
+
+func (t *TableInfo) index(name string, unique bool) *IndexInfo {
+	for _, idx := range t.Indexes {
+		if idx.Name == name {
+			return idx
+		}
+	}
+
+	idx := &IndexInfo{Name: name, Unique: unique}
+	t.Indexes = append(t.Indexes, idx)
+
+	return idx
+}
+
+func (t *TableInfo) foreignKey(name, refTable string) *ForeignKeyInfo {
+	for _, fk := range t.ForeignKeys {
+		if fk.Name == name {
+			return fk
+		}
+	}
+
+	fk := &ForeignKeyInfo{Name: name, RefTable: refTable}
+	t.ForeignKeys = append(t.ForeignKeys, fk)
+
+	return fk
+}
+
+var (
+	_ Migrator = MySQLDialecter
+	_ Migrator = PostgresDialecter
+	_ Migrator = SQLiteDialecter
+)
+
+// queryRows run query and call scan for each row
+func queryRows(ctx context.Context, db *sql.DB, scan func(*sql.Rows) error, query string, args ...interface{}) error {
+	if db == nil {
+		return ErrNoDB
+	}
+
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		if err = scan(rows); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+var spaces = regexp.MustCompile(`\s+`)
+
+func normalizeType(typ string, aliases map[string]string) string {
+	typ = strings.ToLower(strings.TrimSpace(spaces.ReplaceAllString(typ, " ")))
+	typ = strings.ReplaceAll(typ, ", ", ",")
+
+	name, args := typ, ""
+	if idx := strings.IndexByte(typ, '('); idx > 0 {
+		name, args = strings.TrimSpace(typ[:idx]), typ[idx:]
+	}
+	if v, ok := aliases[name+args]; ok {
+		return v
+	}
+	if v, ok := aliases[name]; ok {
+		return v + args
+	}
+
+	return name + args
+}
+
+// unquoteLiteral value of string literal v, ok is false if v is not one
+func unquoteLiteral(v string) (s string, ok bool) {
+	if len(v) < 2 || v[0] != '\'' || v[len(v)-1] != '\'' {
+		return "", false
+	}
+
+	return strings.ReplaceAll(v[1:len(v)-1], "''", "'"), true
+}
+
+// normalizeDefault unquoted string literal, or upper case expression without spaces
+func normalizeDefault(v string) string {
+	v = strings.TrimSpace(v)
+	if s, ok := unquoteLiteral(v); ok {
+		return s
+	}
+
+	return strings.ToUpper(spaces.ReplaceAllString(v, ""))
+}
+
+// mysql
+
+func (m MySQL) Table(ctx context.Context, schema, name string) (*TableInfo, error) {
+	where, args := "TABLE_SCHEMA = DATABASE() AND TABLE_NAME = ?", []interface{}{name}
+	if schema != "" {
+		where, args = "TABLE_SCHEMA = ? AND TABLE_NAME = ?", []interface{}{schema, name}
+	}
+
+	t := &TableInfo{Schema: schema, Name: name}
+	err := queryRows(ctx, m.db, func(rows *sql.Rows) error {
+		c := &ColumnInfo{}
+		var nullable string
+		if err := rows.Scan(&c.Name, &c.Type, &nullable, &c.Default, &c.Comment); err != nil {
+			return err
+		}
+		c.NotNull = nullable == "NO"
+		t.Columns = append(t.Columns, c)
+
+		return nil
+	}, "SELECT COLUMN_NAME,COLUMN_TYPE,IS_NULLABLE,COLUMN_DEFAULT,COLUMN_COMMENT FROM information_schema.COLUMNS WHERE "+where+" ORDER BY ORDINAL_POSITION", args...)
+	if err != nil || len(t.Columns) == 0 {
+		return nil, err
+	}
+
+	err = queryRows(ctx, m.db, func(rows *sql.Rows) error {
+		var idx, col string
+		var nonUnique bool
+		if err := rows.Scan(&idx, &nonUnique, &col); err != nil {
+			return err
+		}
+
+		if idx == "PRIMARY" {
+			t.PrimaryKey = append(t.PrimaryKey, col)
+		} else {
+			i := t.index(idx, !nonUnique)
+			i.Columns = append(i.Columns, col)
+		}
+
+		return nil
+	}, "SELECT INDEX_NAME,NON_UNIQUE,COLUMN_NAME FROM information_schema.STATISTICS WHERE "+where+" ORDER BY INDEX_NAME,SEQ_IN_INDEX", args...)
+	if err != nil {
+		return nil, err
+	}
+
+	err = queryRows(ctx, m.db, func(rows *sql.Rows) error {
+		var name, col, refTable, refCol string
+		if err := rows.Scan(&name, &col, &refTable, &refCol); err != nil {
+			return err
+		}
+
+		fk := t.foreignKey(name, refTable)
+		fk.Columns = append(fk.Columns, col)
+		fk.RefColumns = append(fk.RefColumns, refCol)
+
+		return nil
+	}, "SELECT CONSTRAINT_NAME,COLUMN_NAME,REFERENCED_TABLE_NAME,REFERENCED_COLUMN_NAME FROM information_schema.KEY_COLUMN_USAGE WHERE "+where+
+		" AND REFERENCED_TABLE_NAME IS NOT NULL ORDER BY CONSTRAINT_NAME,ORDINAL_POSITION", args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+var mysqlTypes = map[string]string{
+	"bool":             "tinyint(1)",
+	"boolean":          "tinyint(1)",
+	"integer":          "int",
+	"numeric":          "decimal",
+	"real":             "double",
+	"double precision": "double",
+}
+
+var mysqlIntWidth = regexp.MustCompile(`^(tinyint|smallint|mediumint|int|bigint)\(\d+\)`)
+
+// NormalizeType display width of ints is ignored except tinyint(1), which is boolean
+func (MySQL) NormalizeType(typ string) string {
+	typ = normalizeType(typ, mysqlTypes)
+	if strings.HasPrefix(typ, "tinyint(1)") {
+		return typ
+	}
+
+	return mysqlIntWidth.ReplaceAllString(typ, "$1")
+}
+
+// NormalizeDefault information_schema has string defaults without quotes, so only literals and CURRENT_TIMESTAMP are changed
+func (MySQL) NormalizeDefault(v string) string {
+	if s, ok := unquoteLiteral(strings.TrimSpace(v)); ok {
+		return strings.ReplaceAll(s, `\\`, `\`)
+	}
+	if mysqlNow.MatchString(v) {
+		return strings.ToUpper(spaces.ReplaceAllString(v, ""))
+	}
+
+	return v
+}
+
+func (MySQL) Alter() AlterStyle {
+	return AlterModify
+}
+
+// postgres
+
+const postgresNamespace = "n.nspname = COALESCE(NULLIF($2,''),CURRENT_SCHEMA())"
+
+func (p Postgres) Table(ctx context.Context, schema, name string) (*TableInfo, error) {
+	t := &TableInfo{Schema: schema, Name: name}
+	err := queryRows(ctx, p.db, func(rows *sql.Rows) error {
+		c := &ColumnInfo{}
+		var comment sql.NullString
+		if err := rows.Scan(&c.Name, &c.Type, &c.NotNull, &c.Default, &comment); err != nil {
+			return err
+		}
+		c.Comment = comment.String
+		t.Columns = append(t.Columns, c)
+
+		return nil
+	}, "SELECT a.attname,FORMAT_TYPE(a.atttypid,a.atttypmod),a.attnotnull,PG_GET_EXPR(d.adbin,d.adrelid),COL_DESCRIPTION(c.oid,a.attnum)"+
+		" FROM pg_catalog.pg_attribute a JOIN pg_catalog.pg_class c ON c.oid = a.attrelid JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace"+
+		" LEFT JOIN pg_catalog.pg_attrdef d ON d.adrelid = a.attrelid AND d.adnum = a.attnum"+
+		" WHERE c.relname = $1 AND c.relkind = 'r' AND "+postgresNamespace+" AND a.attnum > 0 AND NOT a.attisdropped ORDER BY a.attnum", name, schema)
+	if err != nil || len(t.Columns) == 0 {
+		return nil, err
+	}
+
+	err = queryRows(ctx, p.db, func(rows *sql.Rows) error {
+		var idx, col string
+		var unique, primary, constraint bool
+		if err := rows.Scan(&idx, &unique, &primary, &constraint, &col); err != nil {
+			return err
+		}
+
+		if primary {
+			t.PrimaryKey = append(t.PrimaryKey, col)
+		} else {
+			i := t.index(idx, unique)
+			i.Constraint = constraint
+			i.Columns = append(i.Columns, col)
+		}
+
+		return nil
+	}, "SELECT i.relname,ix.indisunique,ix.indisprimary,"+
+		"EXISTS(SELECT 1 FROM pg_catalog.pg_constraint con WHERE con.conindid = ix.indexrelid AND con.contype = 'u'),a.attname FROM pg_catalog.pg_index ix"+
+		" JOIN pg_catalog.pg_class t ON t.oid = ix.indrelid JOIN pg_catalog.pg_class i ON i.oid = ix.indexrelid"+
+		" JOIN pg_catalog.pg_namespace n ON n.oid = t.relnamespace"+
+		" JOIN LATERAL UNNEST(ix.indkey) WITH ORDINALITY AS k(attnum,ord) ON TRUE"+
+		" JOIN pg_catalog.pg_attribute a ON a.attrelid = t.oid AND a.attnum = k.attnum"+
+		" WHERE t.relname = $1 AND "+postgresNamespace+" ORDER BY i.relname,k.ord", name, schema)
+	if err != nil {
+		return nil, err
+	}
+
+	err = queryRows(ctx, p.db, func(rows *sql.Rows) error {
+		var name, col, refTable, refCol string
+		if err := rows.Scan(&name, &col, &refTable, &refCol); err != nil {
+			return err
+		}
+
+		fk := t.foreignKey(name, refTable)
+		fk.Columns = append(fk.Columns, col)
+		fk.RefColumns = append(fk.RefColumns, refCol)
+
+		return nil
+	}, "SELECT con.conname,a.attname,rt.relname,ra.attname FROM pg_catalog.pg_constraint con"+
+		" JOIN pg_catalog.pg_class t ON t.oid = con.conrelid JOIN pg_catalog.pg_namespace n ON n.oid = t.relnamespace"+
+		" JOIN pg_catalog.pg_class rt ON rt.oid = con.confrelid"+
+		" JOIN LATERAL UNNEST(con.conkey,con.confkey) WITH ORDINALITY AS k(attnum,refnum,ord) ON TRUE"+
+		" JOIN pg_catalog.pg_attribute a ON a.attrelid = con.conrelid AND a.attnum = k.attnum"+
+		" JOIN pg_catalog.pg_attribute ra ON ra.attrelid = con.confrelid AND ra.attnum = k.refnum"+
+		" WHERE con.contype = 'f' AND t.relname = $1 AND "+postgresNamespace+" ORDER BY con.conname,k.ord", name, schema)
+	if err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+var postgresTypes = map[string]string{
+	"varchar":     "character varying",
+	"char":        "character",
+	"timestamptz": "timestamp with time zone",
+	"timestamp":   "timestamp without time zone",
+	"int":         "integer",
+	"int4":        "integer",
+	"serial":      "integer",
+	"serial4":     "integer",
+	"int8":        "bigint",
+	"bigserial":   "bigint",
+	"serial8":     "bigint",
+	"int2":        "smallint",
+	"smallserial": "smallint",
+	"serial2":     "smallint",
+	"decimal":     "numeric",
+	"float8":      "double precision",
+	"float4":      "real",
+	"bool":        "boolean",
+}
+
+// NormalizeType serial types are their int types
+func (Postgres) NormalizeType(typ string) string {
+	return normalizeType(typ, postgresTypes)
+}
+
+var postgresCast = regexp.MustCompile(`::[a-z][a-z0-9_ ]*(\([0-9, ]*\))?(\[\])?$`)
+
+// isWrapped whether s is enclosed in a pair of parentheses, like `(-1)` but not `(a) + (b)`
+func isWrapped(s string) bool {
+	if len(s) < 2 || s[0] != '(' || s[len(s)-1] != ')' {
+		return false
+	}
+
+	depth := 0
+	for i := 0; i < len(s)-1; i++ {
+		switch s[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// NormalizeDefault casts and parentheses are removed, such as `'a'::character varying` and `(-1)`
+func (Postgres) NormalizeDefault(v string) string {
+	v = strings.TrimSpace(v)
+	for {
+		s := strings.TrimSpace(postgresCast.ReplaceAllString(v, ""))
+		if isWrapped(s) {
+			s = strings.TrimSpace(s[1 : len(s)-1])
+		}
+		if s == v {
+			break
+		}
+		v = s
+	}
+
+	return normalizeDefault(v)
+}
+
+func (Postgres) Alter() AlterStyle {
+	return AlterStandard
+}
+
+// sqlite
+
+func (s SQLite) Table(ctx context.Context, schema, name string) (*TableInfo, error) {
+	prefix := ""
+	if schema != "" {
+		prefix = s.Queto(schema) + "."
+	}
+	table := s.Queto(name)
+
+	t := &TableInfo{Schema: schema, Name: name}
+	var pks []string
+	err := queryRows(ctx, s.db, func(rows *sql.Rows) error {
+		c := &ColumnInfo{}
+		var cid, pk int
+		if err := rows.Scan(&cid, &c.Name, &c.Type, &c.NotNull, &c.Default, &pk); err != nil {
+			return err
+		}
+		t.Columns = append(t.Columns, c)
+
+		// pk is the index in the primary key, starting from 1
+		for len(pks) < pk {
+			pks = append(pks, "")
+		}
+		if pk > 0 {
+			pks[pk-1] = c.Name
+		}
+
+		return nil
+	}, "PRAGMA "+prefix+"table_info("+table+")")
+	if err != nil || len(t.Columns) == 0 {
+		return nil, err
+	}
+	t.PrimaryKey = pks
+
+	var names []string
+	err = queryRows(ctx, s.db, func(rows *sql.Rows) error {
+		var seq int
+		var idx, origin string
+		var unique, partial bool
+		if err := rows.Scan(&seq, &idx, &unique, &origin, &partial); err != nil {
+			return err
+		}
+
+		// origin is c(CREATE INDEX), u(UNIQUE constraint) or pk
+		if origin != "pk" {
+			t.index(idx, unique).Constraint = origin == "u"
+			names = append(names, idx)
+		}
+
+		return nil
+	}, "PRAGMA "+prefix+"index_list("+table+")")
+	if err != nil {
+		return nil, err
+	}
+
+	for _, idx := range names {
+		i := t.index(idx, false)
+		err = queryRows(ctx, s.db, func(rows *sql.Rows) error {
+			var seq, cid int
+			var col sql.NullString
+			if err := rows.Scan(&seq, &cid, &col); err != nil {
+				return err
+			}
+			i.Columns = append(i.Columns, col.String)
+
+			return nil
+		}, "PRAGMA "+prefix+"index_info("+s.Queto(idx)+")")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = queryRows(ctx, s.db, func(rows *sql.Rows) error {
+		var id, seq int
+		var refTable, col string
+		var refCol sql.NullString
+		var onUpdate, onDelete, match string
+		if err := rows.Scan(&id, &seq, &refTable, &col, &refCol, &onUpdate, &onDelete, &match); err != nil {
+			return err
+		}
+
+		// foreign keys have no name in sqlite, id is used to group columns
+		if seq == 0 {
+			t.ForeignKeys = append(t.ForeignKeys, &ForeignKeyInfo{RefTable: refTable})
+		}
+		fk := t.ForeignKeys[len(t.ForeignKeys)-1]
+		fk.Columns = append(fk.Columns, col)
+		fk.RefColumns = append(fk.RefColumns, refCol.String)
+
+		return nil
+	}, "PRAGMA "+prefix+"foreign_key_list("+table+")")
+	if err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+func (SQLite) NormalizeType(typ string) string {
+	return normalizeType(typ, nil)
+}
+
+// NormalizeDefault sqlite reports DEFAULT (expr) without the parentheses
+func (SQLite) NormalizeDefault(v string) string {
+	v = strings.TrimSpace(v)
+	if isWrapped(v) {
+		v = v[1 : len(v)-1]
+	}
+
+	return normalizeDefault(v)
+}
+
+// Alter sqlite can not change columns and constraints without rebuilding the table
+func (SQLite) Alter() AlterStyle {
+	return AlterLimited
+}
@@ -0,0 +1,49 @@
+package dialect
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeType(t *testing.T) {
+	for _, v := range [][3]string{
+		{"mysql", "int(11)", "INT"},
+		{"mysql", "int(10) unsigned", "INT UNSIGNED"},
+		{"mysql", "tinyint(1)", "BOOLEAN"},
+		{"mysql", "decimal(10, 2)", "NUMERIC(10,2)"},
+		{"mysql", "varchar(64)", "VARCHAR(64)"},
+		{"postgres", "character varying(64)", "VARCHAR(64)"},
+		{"postgres", "bigint", "BIGSERIAL"},
+		{"postgres", "timestamp with time zone", "TIMESTAMPTZ"},
+		{"postgres", "double precision", "float8"},
+		{"sqlite3", "varchar(64)", "VARCHAR(64)"},
+	} {
+		m := NewDialecter(v[0], nil).(Migrator)
+		assert.Equal(t, m.NormalizeType(v[1]), m.NormalizeType(v[2]), v[0]+" "+v[1])
+	}
+
+	assert.NotEqual(t, MySQLDialecter.NormalizeType("tinyint(1)"), MySQLDialecter.NormalizeType("tinyint(4)"))
+	assert.NotEqual(t, PostgresDialecter.NormalizeType("varchar(64)"), PostgresDialecter.NormalizeType("varchar(32)"))
+}
+
+func TestNormalizeDefault(t *testing.T) {
+	for _, v := range [][3]string{
+		{"mysql", "active", "'active'"},
+		{"mysql", `a\b`, `'a\\b'`},
+		{"mysql", "CURRENT_TIMESTAMP(3)", "current_timestamp(3)"},
+		{"postgres", "'active'::character varying", "'active'"},
+		{"postgres", "'{}'::jsonb", "'{}'"},
+		{"postgres", "(-1)", "-1"},
+		{"postgres", "now()", "NOW()"},
+		{"sqlite3", "'it''s'", "'it''s'"},
+		{"sqlite3", "CURRENT_TIMESTAMP", "current_timestamp"},
+		{"sqlite3", "(datetime('now'))", "datetime('now')"},
+	} {
+		m := NewDialecter(v[0], nil).(Migrator)
+		assert.Equal(t, m.NormalizeDefault(v[1]), m.NormalizeDefault(v[2]), v[0]+" "+v[1])
+	}
+
+	assert.NotEqual(t, PostgresDialecter.NormalizeDefault("(a) + (b)"), PostgresDialecter.NormalizeDefault("a) + (b"))
+	assert.NotEqual(t, SQLiteDialecter.NormalizeDefault("'a'"), SQLiteDialecter.NormalizeDefault("'A'"))
+}
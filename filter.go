@@ -0,0 +1,442 @@
+package layer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/meilihao/layer/clause"
+	"github.com/meilihao/layer/schema"
+)
+
+var (
+	ErrFilterUnknownField    = errors.New("layer : filter unknown field")
+	ErrFilterUnknownOperator = errors.New("layer : filter unknown operator")
+	ErrFilterInvalidValue    = errors.New("layer : filter invalid value")
+	ErrFilterInvalidDocument = errors.New("layer : filter invalid document")
+)
+
+// filter error codes, safe to return to clients
+const (
+	FilterCodeUnknownField    = "unknown_field"
+	FilterCodeUnknownOperator = "unknown_operator"
+	FilterCodeInvalidValue    = "invalid_value"
+	FilterCodeInvalidDocument = "invalid_document"
+)
+
+// filter operators
+const (
+	FilterEq     = "eq"
+	FilterNe     = "ne"
+	FilterGt     = "gt"
+	FilterGte    = "gte"
+	FilterLt     = "lt"
+	FilterLte    = "lte"
+	FilterLike   = "like"
+	FilterIn     = "in"
+	FilterNin    = "nin"
+	FilterIsNull = "null" // true is IS NULL, false is IS NOT NULL
+)
+
+// FilterError an invalid field, operator or value, it only contains what the client sent
+type FilterError struct {
+	Field string `json:"field"`
+	Op    string `json:"op,omitempty"`
+	Code  string `json:"code"`
+	Err   error  `json:"-"`
+}
+
+func (e *FilterError) Error() string {
+	if e.Op != "" {
+		return fmt.Sprintf("%s: %s[%s]", e.Err.Error(), e.Field, e.Op)
+	}
+
+	return fmt.Sprintf("%s: %s", e.Err.Error(), e.Field)
+}
+
+func (e *FilterError) Unwrap() error {
+	return e.Err
+}
+
+// FilterErrors all errors found while parsing a filter
+type FilterErrors []*FilterError
+
+func (es FilterErrors) Error() string {
+	ss := make([]string, 0, len(es))
+	for _, e := range es {
+		ss = append(ss, e.Error())
+	}
+
+	return strings.Join(ss, "; ")
+}
+
+// Is check every error
+func (es FilterErrors) Is(target error) bool {
+	for _, e := range es {
+		if errors.Is(e, target) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Filter where and order by parsed from client input
+type Filter struct {
+	Where   clause.Where
+	OrderBy clause.OrderBy
+}
+
+type filterParser struct {
+	sc      *schema.Schema
+	allow   map[string]bool
+	ignore  map[string]bool
+	sortKey string
+	columns map[string]*schema.Column // by DBName
+	errs    FilterErrors
+	filter  *Filter
+}
+
+// FilterOption option of ParseFilter
+type FilterOption func(*filterParser)
+
+// FilterAllow expose columns by db name, in addition to the ones tagged with `filter`
+func FilterAllow(columns ...string) FilterOption {
+	return func(p *filterParser) {
+		for _, c := range columns {
+			p.allow[c] = true
+		}
+	}
+}
+
+// FilterIgnore skip keys which are not filters, like "page" and "size"
+func FilterIgnore(keys ...string) FilterOption {
+	return func(p *filterParser) {
+		for _, k := range keys {
+			p.ignore[k] = true
+		}
+	}
+}
+
+// FilterSortKey key of sort, default is "sort"
+func FilterSortKey(key string) FilterOption {
+	return func(p *filterParser) {
+		p.sortKey = key
+	}
+}
+
+func newFilterParser(sc *schema.Schema, opts []FilterOption) *filterParser {
+	p := &filterParser{
+		sc:      sc,
+		allow:   make(map[string]bool),
+		ignore:  make(map[string]bool),
+		sortKey: "sort",
+		columns: make(map[string]*schema.Column),
+		filter:  &Filter{},
+	}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	// fields are named by db name, only exposed columns are accepted
+	for _, c := range sc.Columns {
+		if p.allow[c.DBName] || c.Field.Filterable {
+			p.columns[c.DBName] = c
+		}
+	}
+
+	return p
+}
+
+// ParseFilter parse url query like `?status=active&age[gte]=18&id[in]=1,2&sort=-created_at,name`.
+// fields are db names of the columns exposed by FilterAllow or tag `filter`, values are converted by their DataType.
+// errors are FilterErrors which are safe to return to clients.
+func ParseFilter(sc *schema.Schema, values url.Values, opts ...FilterOption) (*Filter, error) {
+	p := newFilterParser(sc, opts)
+
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		if p.ignore[k] {
+			continue
+		}
+
+		if k == p.sortKey {
+			for _, v := range values[k] {
+				p.parseSort(strings.Split(v, ","))
+			}
+			continue
+		}
+
+		field, op := k, FilterEq
+		if i := strings.IndexByte(k, '['); i > 0 && strings.HasSuffix(k, "]") {
+			field, op = k[:i], k[i+1:len(k)-1]
+		}
+
+		var v interface{}
+		switch vs := values[k]; op {
+		case FilterIn, FilterNin:
+			var list []interface{}
+			for _, s := range vs {
+				for _, x := range strings.Split(s, ",") {
+					list = append(list, x)
+				}
+			}
+			v = list
+		case FilterEq:
+			// repeated key is IN
+			if len(vs) > 1 {
+				op, v = FilterIn, toInterfaces(vs)
+			} else {
+				v = vs[0]
+			}
+		default:
+			v = vs[len(vs)-1]
+		}
+
+		p.parseCond(field, op, v)
+	}
+
+	return p.result()
+}
+
+// ParseFilterJSON parse json document like `{"status":"active","age":{"gte":18},"id":{"in":[1,2]},"sort":["-created_at","name"]}`.
+// sort can also be a string separated by comma.
+func ParseFilterJSON(sc *schema.Schema, data []byte, opts ...FilterOption) (*Filter, error) {
+	p := newFilterParser(sc, opts)
+
+	var doc map[string]json.RawMessage
+	d := json.NewDecoder(strings.NewReader(string(data)))
+	d.UseNumber()
+	if err := d.Decode(&doc); err != nil {
+		return nil, FilterErrors{{Code: FilterCodeInvalidDocument, Err: ErrFilterInvalidDocument}}
+	}
+
+	keys := make([]string, 0, len(doc))
+	for k := range doc {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		if p.ignore[k] {
+			continue
+		}
+
+		var v interface{}
+		d := json.NewDecoder(strings.NewReader(string(doc[k])))
+		d.UseNumber()
+		_ = d.Decode(&v) // doc[k] is valid json
+
+		if k == p.sortKey {
+			switch s := v.(type) {
+			case string:
+				p.parseSort(strings.Split(s, ","))
+			case []interface{}:
+				cols := make([]string, 0, len(s))
+				for _, x := range s {
+					if str, ok := x.(string); ok {
+						cols = append(cols, str)
+					} else {
+						p.addErr(k, "", FilterCodeInvalidValue, ErrFilterInvalidValue)
+					}
+				}
+				p.parseSort(cols)
+			default:
+				p.addErr(k, "", FilterCodeInvalidValue, ErrFilterInvalidValue)
+			}
+			continue
+		}
+
+		ops, ok := v.(map[string]interface{})
+		if !ok {
+			p.parseCond(k, FilterEq, v)
+			continue
+		}
+
+		names := make([]string, 0, len(ops))
+		for op := range ops {
+			names = append(names, op)
+		}
+		sort.Strings(names)
+
+		for _, op := range names {
+			p.parseCond(k, op, ops[op])
+		}
+	}
+
+	return p.result()
+}
+
+func (p *filterParser) result() (*Filter, error) {
+	if len(p.errs) > 0 {
+		return nil, p.errs
+	}
+
+	return p.filter, nil
+}
+
+func (p *filterParser) addErr(field, op, code string, err error) {
+	p.errs = append(p.errs, &FilterError{Field: field, Op: op, Code: code, Err: err})
+}
+
+func (p *filterParser) parseSort(cols []string) {
+	for _, s := range cols {
+		s = strings.TrimSpace(s)
+		if s == "" {
+			continue
+		}
+
+		desc := strings.HasPrefix(s, "-")
+		name := strings.TrimLeft(s, "+-")
+
+		c, ok := p.columns[name]
+		if !ok {
+			p.addErr(name, "", FilterCodeUnknownField, ErrFilterUnknownField)
+			continue
+		}
+
+		if desc {
+			p.filter.OrderBy.Columns = append(p.filter.OrderBy.Columns, "-"+c.RawName)
+		} else {
+			p.filter.OrderBy.Columns = append(p.filter.OrderBy.Columns, c.RawName)
+		}
+	}
+}
+
+func (p *filterParser) parseCond(field, op string, v interface{}) {
+	c, ok := p.columns[field]
+	if !ok {
+		p.addErr(field, "", FilterCodeUnknownField, ErrFilterUnknownField)
+		return
+	}
+
+	var e clause.Expression
+	switch op {
+	case FilterIsNull:
+		b, err := convertFilterValue(schema.Bool, v)
+		if err != nil {
+			break
+		}
+		if b.(bool) {
+			e = clause.IsNULL(c.RawName)
+		} else {
+			e = clause.NotNULL(c.RawName)
+		}
+	case FilterIn, FilterNin:
+		list, ok := v.([]interface{})
+		if !ok {
+			list = []interface{}{v}
+		}
+		args := make([]interface{}, 0, len(list))
+		for _, x := range list {
+			arg, err := convertFilterValue(c.Field.DataType, x)
+			if err != nil {
+				args = nil
+				break
+			}
+			args = append(args, arg)
+		}
+		if len(args) == 0 {
+			break
+		}
+		if op == FilterIn {
+			e = clause.In(c.RawName, args...)
+		} else {
+			e = clause.NotIn(c.RawName, args...)
+		}
+	case FilterLike:
+		if c.Field.DataType != schema.String {
+			p.addErr(field, op, FilterCodeUnknownOperator, ErrFilterUnknownOperator)
+			return
+		}
+		fallthrough
+	case FilterEq, FilterNe, FilterGt, FilterGte, FilterLt, FilterLte:
+		arg, err := convertFilterValue(c.Field.DataType, v)
+		if err != nil {
+			break
+		}
+		e = filterOps[op](c.RawName, arg)
+	default:
+		p.addErr(field, op, FilterCodeUnknownOperator, ErrFilterUnknownOperator)
+		return
+	}
+
+	if e == nil {
+		p.addErr(field, op, FilterCodeInvalidValue, ErrFilterInvalidValue)
+		return
+	}
+
+	p.filter.Where.Exprs = append(p.filter.Where.Exprs, e)
+}
+
+var filterOps = map[string]func(string, interface{}) clause.Expression{
+	FilterEq:   func(col string, v interface{}) clause.Expression { return clause.Eq(col, v) },
+	FilterNe:   func(col string, v interface{}) clause.Expression { return clause.Neq(col, v) },
+	FilterGt:   func(col string, v interface{}) clause.Expression { return clause.Gt(col, v) },
+	FilterGte:  func(col string, v interface{}) clause.Expression { return clause.Gte(col, v) },
+	FilterLt:   func(col string, v interface{}) clause.Expression { return clause.Lt(col, v) },
+	FilterLte:  func(col string, v interface{}) clause.Expression { return clause.Lte(col, v) },
+	FilterLike: func(col string, v interface{}) clause.Expression { return clause.Like(col, v) },
+}
+
+// convertFilterValue convert string from url or json value to typ, unknown types are kept as string
+func convertFilterValue(typ schema.DataType, v interface{}) (interface{}, error) {
+	switch x := v.(type) {
+	case string:
+		switch typ {
+		case schema.Bool:
+			return strconv.ParseBool(x)
+		case schema.Int:
+			return strconv.ParseInt(x, 10, 64)
+		case schema.Uint:
+			return strconv.ParseUint(x, 10, 64)
+		case schema.Float:
+			return strconv.ParseFloat(x, 64)
+		case schema.Time:
+			return time.Parse(time.RFC3339, x)
+		case schema.Bytes:
+			return nil, ErrFilterInvalidValue
+		}
+		return x, nil
+	case json.Number:
+		switch typ {
+		case schema.Int:
+			return x.Int64()
+		case schema.Uint:
+			if n, err := strconv.ParseUint(x.String(), 10, 64); err == nil {
+				return n, nil
+			}
+		case schema.Float:
+			if f, err := x.Float64(); err == nil && !math.IsInf(f, 0) {
+				return f, nil
+			}
+		}
+	case bool:
+		if typ == schema.Bool {
+			return x, nil
+		}
+	}
+
+	return nil, ErrFilterInvalidValue
+}
+
+func toInterfaces(ss []string) []interface{} {
+	vs := make([]interface{}, len(ss))
+	for i, s := range ss {
+		vs[i] = s
+	}
+
+	return vs
+}
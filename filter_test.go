@@ -0,0 +1,70 @@
+package layer
+
+import (
+	"encoding/json"
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/meilihao/layer/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+type filterUser struct {
+	Id        int    `layer:";pk;filter"`
+	Status    string `layer:";filter"`
+	Age       int
+	Score     float64
+	Password  string
+	CreatedAt time.Time `layer:";filter"`
+}
+
+func TestFilter(t *testing.T) {
+	sc, err := schema.Parse(&filterUser{}, l.opts.nameMapper)
+	assert.NoError(t, err)
+
+	q, _ := url.ParseQuery("status=active&age[gte]=18&id[in]=1,2&id[nin]=3&created_at[null]=false&sort=-created_at,age&page=2")
+	f, err := ParseFilter(sc, q, FilterAllow("age", "score"), FilterIgnore("page"))
+	assert.NoError(t, err)
+
+	sql, args, err := Select("Id").From(&filterUser{}).Where(f.Where.Exprs...).OrderBy(f.OrderBy.Columns...).Build(l, nil, 0)
+	assert.NoError(t, err)
+	assert.EqualValues(t, "SELECT `id` FROM `filter_user` WHERE `age` >= ? AND `created_at` IS NOT NULL AND `id` IN (?,?) AND `id` <> ? AND `status` = ? ORDER BY `created_at` DESC,`age` ASC", sql)
+	assert.EqualValues(t, []interface{}{int64(18), int64(1), int64(2), int64(3), "active"}, args)
+
+	// repeated key is IN
+	q, _ = url.ParseQuery("status=a&status=b")
+	f, err = ParseFilter(sc, q)
+	assert.NoError(t, err)
+	sql, args, err = Select("Id").From(&filterUser{}).Where(f.Where.Exprs...).Build(l, nil, 0)
+	assert.NoError(t, err)
+	assert.EqualValues(t, "SELECT `id` FROM `filter_user` WHERE `status` IN (?,?)", sql)
+	assert.EqualValues(t, []interface{}{"a", "b"}, args)
+
+	f, err = ParseFilterJSON(sc, []byte(`{"status":{"like":"a%"},"score":{"lt":9.5,"gt":1},"id":{"in":[1,2]},"created_at":{"gte":"2020-01-02T03:04:05Z"},"sort":["-id"]}`), FilterAllow("score"))
+	assert.NoError(t, err)
+	sql, args, err = Select("Id").From(&filterUser{}).Where(f.Where.Exprs...).OrderBy(f.OrderBy.Columns...).Build(l, nil, 0)
+	assert.NoError(t, err)
+	assert.EqualValues(t, "SELECT `id` FROM `filter_user` WHERE `created_at` >= ? AND `id` IN (?,?) AND `score` > ? AND `score` < ? AND `status` LIKE ? ORDER BY `id` DESC", sql)
+	assert.EqualValues(t, []interface{}{time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC), int64(1), int64(2), float64(1), 9.5, "a%"}, args)
+
+	// every error is reported, they only contain what the client sent
+	q, _ = url.ParseQuery("password=x&age[gte]=abc&id[regex]=1&age[like]=1&sort=password")
+	_, err = ParseFilter(sc, q, FilterAllow("age"))
+	var es FilterErrors
+	assert.True(t, errors.As(err, &es))
+	assert.True(t, errors.Is(err, ErrFilterUnknownField))
+	assert.True(t, errors.Is(err, ErrFilterUnknownOperator))
+	assert.True(t, errors.Is(err, ErrFilterInvalidValue))
+	data, _ := json.Marshal(es)
+	assert.EqualValues(t, `[{"field":"age","op":"gte","code":"invalid_value"},{"field":"age","op":"like","code":"unknown_operator"},{"field":"id","op":"regex","code":"unknown_operator"},{"field":"password","code":"unknown_field"},{"field":"password","code":"unknown_field"}]`, string(data))
+	assert.EqualValues(t, "layer : filter invalid value: age[gte]; layer : filter unknown operator: age[like]; layer : filter unknown operator: id[regex]; layer : filter unknown field: password; layer : filter unknown field: password", err.Error())
+
+	_, err = ParseFilterJSON(sc, []byte(`{"id":{"in":[1.5]},"status":null}`))
+	assert.True(t, errors.Is(err, ErrFilterInvalidValue))
+	assert.EqualValues(t, 2, len(err.(FilterErrors)))
+
+	_, err = ParseFilterJSON(sc, []byte(`[1]`))
+	assert.True(t, errors.Is(err, ErrFilterInvalidDocument))
+}
@@ -32,6 +32,7 @@ type QuerySession struct {
 	noVersion    bool
 	unscoped     bool
 	distinct     bool
+	locking      clause.Locking
 }
 
 func (se *QuerySession) Unscoped() *QuerySession {
@@ -46,6 +47,20 @@ func (se *QuerySession) Distinct() *QuerySession {
 	return se
 }
 
+// ForUpdate lock the found rows by FOR UPDATE [NOWAIT|SKIP LOCKED], use it in a transaction
+func (se *QuerySession) ForUpdate(option ...string) *QuerySession {
+	se.locking = newLocking(clause.LockUpdate, option)
+
+	return se
+}
+
+// ForShare lock the found rows by FOR SHARE [NOWAIT|SKIP LOCKED], use it in a transaction
+func (se *QuerySession) ForShare(option ...string) *QuerySession {
+	se.locking = newLocking(clause.LockShare, option)
+
+	return se
+}
+
 func (se *QuerySession) Select(cols ...string) *QuerySession {
 	if len(se.omits) > 0 {
 		return se
@@ -141,7 +156,9 @@ func (se *QuerySession) Find(value interface{}) (interface{}, error) {
 	if se.err != nil {
 		return nil, se.err
 	}
-	se.value, _ = utils.PtrValue(value)
+	if se.value, _, se.err = utils.PtrValue(value); se.err != nil {
+		return nil, se.err
+	}
 	if se.table == "" {
 		se.table = se.schema.DBName
 	}
@@ -187,23 +204,20 @@ func (se *QuerySession) Find(value interface{}) (interface{}, error) {
 	se.builder = NewSQLBuilder(se.l, se.schema, 128)
 	se.clauses[clause.ClauseFrom] = clause.From{
 		Tables: []clause.Table{
-			clause.Table{
-				Name: se.table,
-			},
+			modelTable(se.schema, se.table),
 		},
 	}
-	se.err = se.clauses.Build(se.builder, clause.ClauseSelect, clause.ClauseFrom, clause.ClauseWhere)
+	se.clauses[clause.ClauseLocking] = se.locking
+	se.err = se.clauses.Build(se.builder, clause.ClauseSelect, clause.ClauseFrom, clause.ClauseWhere, clause.ClauseLocking)
 
 	if se.err != nil {
 		return nil, se.err
 	}
 
 	now := time.Now()
-	if se.debug {
-		log.Info().Msgf("[%f] %s", time.Now().Sub(now).Seconds(), se.builder.String())
-	}
-
 	if se.dryRun {
+		se.l.logQuery(se.context, &QueryEvent{Op: OpFind, Table: se.table, SQL: se.builder.String()}, se.debug)
+
 		return true, nil
 	}
 
@@ -215,9 +229,9 @@ func (se *QuerySession) query(now time.Time) (interface{}, error) {
 		se.context = context.Background()
 	}
 
-	stmt, err := se.l.db.PrepareContext(se.context, se.builder.String())
+	stmt, err := se.l.conn().PrepareContext(se.context, se.builder.String())
 	if err != nil {
-		return nil, err
+		return nil, se.l.TranslateError(err)
 	}
 
 	defer func() {
@@ -280,10 +294,6 @@ func (se *QuerySession) query1(s *sql.Stmt, v reflect.Value, now time.Time) (_ b
 		a = append(a, i)
 	}
 
-	if se.debug {
-		log.Info().Msg(se.l.dialecter.Explain(se.builder.String(), a))
-	}
-
 	vs := make([]interface{}, len(se.selectedCols))
 	f := make([]func() error, len(vs))
 	for i, c := range se.selectedCols {
@@ -294,11 +304,22 @@ func (se *QuerySession) query1(s *sql.Stmt, v reflect.Value, now time.Time) (_ b
 		}
 	}
 
-	if err = s.QueryRow(a...).Scan(vs...); err == ErrNoRows {
+	var n int64
+	ev := &QueryEvent{Op: OpFind, Table: se.table, SQL: se.builder.String(), Args: a}
+	ctx := se.l.startQuery(se.context, ev)
+	err = s.QueryRowContext(ctx, a...).Scan(vs...)
+	defer func() {
+		ev.RowsAffected, ev.Err = n, err
+		se.l.endQuery(ctx, ev, se.debug)
+		err = ev.Err
+	}()
+
+	if err == ErrNoRows {
 		return false, nil
 	} else if err != nil {
 		return
 	}
+	n = 1
 
 	for _, i := range f {
 		if i != nil {
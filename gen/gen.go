@@ -0,0 +1,233 @@
+// Package gen generates typed column accessors of models, used by cmd/layergen.
+// models are read by schema.Parse, so the columns are the same as the ones used by layer at runtime.
+package gen
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/format"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/meilihao/layer/schema"
+)
+
+var (
+	ErrNoPackage = errors.New("layer : gen needs a package name")
+)
+
+// Config of Generate
+type Config struct {
+	Package string // package name of the generated file
+	PkgPath string // import path of the package, types of it are not qualified
+}
+
+// Generate write typed columns of models into w, like:
+//
+//	UserCols.Age.Gt(18)       // clause.Expression of `age` > ?
+//	UserCols.Name.Name()      // "Name", raw name for Select() and OrderBy()
+//	UserCols.Age.Set(19)      // clause.Assignment for Set()
+func Generate(w io.Writer, cfg Config, models ...interface{}) error {
+	if cfg.Package == "" {
+		return ErrNoPackage
+	}
+
+	g := &generator{
+		cfg:     cfg,
+		imports: map[string]string{"github.com/meilihao/layer/clause": "clause"},
+	}
+
+	for _, m := range models {
+		sc, err := schema.Parse(m, schema.SnakeNameMapper{})
+		if err != nil {
+			return err
+		}
+
+		g.model(sc)
+	}
+
+	var out bytes.Buffer
+	out.WriteString("// Code generated by layergen. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&out, "package %s\n\nimport (\n", cfg.Package)
+
+	paths := make([]string, 0, len(g.imports))
+	for p := range g.imports {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	for _, p := range paths {
+		fmt.Fprintf(&out, "\t%s %q\n", g.imports[p], p)
+	}
+	out.WriteString(")\n")
+	out.Write(g.body.Bytes())
+
+	src, err := format.Source(out.Bytes())
+	if err != nil {
+		return fmt.Errorf("layer : gen format: %w", err)
+	}
+
+	_, err = w.Write(src)
+
+	return err
+}
+
+type generator struct {
+	cfg     Config
+	imports map[string]string // import path -> name
+	body    bytes.Buffer
+}
+
+func (g *generator) printf(format string, args ...interface{}) {
+	fmt.Fprintf(&g.body, format, args...)
+}
+
+func (g *generator) model(sc *schema.Schema) {
+	name := sc.Name
+	prefix := lowerFirst(name)
+
+	g.printf("\n// %sTable raw table name of %s\n", name, name)
+	g.printf("const %sTable = %q\n", name, sc.RawName)
+
+	g.printf("\n// %sCols typed columns of %s\n", name, name)
+	g.printf("var %sCols = struct {\n", name)
+	for _, c := range sc.Columns {
+		g.printf("\t%s %s%sCol // %s\n", goName(c.RawName), prefix, goName(c.RawName), c.DBName)
+	}
+	g.printf("}{}\n")
+
+	for _, c := range sc.Columns {
+		g.column(prefix+goName(c.RawName)+"Col", c)
+	}
+}
+
+// column methods of typed column, the arg type is the indirect field type, json and xml columns take interface{}
+func (g *generator) column(typ string, c *schema.Column) {
+	f := c.Field
+	raw := fmt.Sprintf("%q", c.RawName)
+
+	arg := "interface{}"
+	if !f.IsJSON && !f.IsXML {
+		arg = g.typeName(f.IndirectFieldType)
+	}
+	nullable := f.IsPointer || f.IsJSON || f.IsXML || f.IndirectFieldType.Kind() == reflect.Interface
+
+	g.printf("\n// %s column %s\ntype %s struct{}\n\n", typ, c.RawName, typ)
+	g.printf("func (%s) Name() string { return %s }\n", typ, raw)
+	g.printf("func (%s) Column() clause.Column { return clause.Column{Name: %s} }\n", typ, raw)
+	g.printf("func (%s) Set(v %s) clause.Assignment { return clause.Assignment{Column: clause.Column{Name: %s}, Value: v} }\n", typ, arg, raw)
+
+	if !f.IsJSON && !f.IsXML {
+		for _, op := range []string{"Eq", "Neq", "Gt", "Gte", "Lt", "Lte"} {
+			g.printf("func (%s) %s(v %s) clause.Expression { return clause.%s(%s, v) }\n", typ, op, arg, op, raw)
+		}
+		if f.IndirectFieldType.Kind() == reflect.String {
+			g.printf("func (%s) Like(v %s) clause.Expression { return clause.Like(%s, v) }\n", typ, arg, raw)
+		}
+		for _, op := range []string{"In", "NotIn"} {
+			g.printf("func (%s) %s(vs ...%s) clause.Expression {\n", typ, op, arg)
+			g.printf("\targs := make([]interface{}, len(vs))\n\tfor i, v := range vs {\n\t\targs[i] = v\n\t}\n")
+			g.printf("\treturn clause.%s(%s, args...)\n}\n", op, raw)
+		}
+	}
+
+	if nullable {
+		g.printf("func (%s) IsNull() clause.Expression { return clause.IsNULL(%s) }\n", typ, raw)
+		g.printf("func (%s) NotNull() clause.Expression { return clause.NotNULL(%s) }\n", typ, raw)
+	}
+}
+
+// typeName go expression of t, named types of other packages are qualified and imported
+func (g *generator) typeName(t reflect.Type) string {
+	if t.Name() != "" {
+		if t.PkgPath() == "" || t.PkgPath() == g.cfg.PkgPath {
+			return t.Name()
+		}
+
+		return g.importName(t.PkgPath()) + "." + t.Name()
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		return "*" + g.typeName(t.Elem())
+	case reflect.Slice:
+		return "[]" + g.typeName(t.Elem())
+	case reflect.Array:
+		return fmt.Sprintf("[%d]%s", t.Len(), g.typeName(t.Elem()))
+	case reflect.Map:
+		return "map[" + g.typeName(t.Key()) + "]" + g.typeName(t.Elem())
+	}
+
+	// interface{}, unnamed struct and others
+	return "interface{}"
+}
+
+// importName import pkgPath with a name unique in the file
+func (g *generator) importName(pkgPath string) string {
+	if name, ok := g.imports[pkgPath]; ok {
+		return name
+	}
+
+	base := pkgPath[strings.LastIndexByte(pkgPath, '/')+1:]
+	base = strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' {
+			return r
+		}
+		return '_'
+	}, base)
+
+	name := base
+	for i := 2; g.used(name); i++ {
+		name = fmt.Sprintf("%s%d", base, i)
+	}
+	g.imports[pkgPath] = name
+
+	return name
+}
+
+func (g *generator) used(name string) bool {
+	for _, v := range g.imports {
+		if v == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// goName exported go identifier of raw name, like user_name -> UserName
+func goName(raw string) string {
+	var b strings.Builder
+
+	upper := true
+	for _, r := range raw {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
+	}
+
+	s := b.String()
+	if s == "" || unicode.IsDigit(rune(s[0])) {
+		s = "C" + s
+	}
+
+	return s
+}
+
+func lowerFirst(s string) string {
+	if s == "" {
+		return s
+	}
+
+	return strings.ToLower(s[:1]) + s[1:]
+}
@@ -0,0 +1,81 @@
+package gen
+
+import (
+	"bytes"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type GenPoint struct {
+	X int
+	Y int
+}
+
+type GenDept struct {
+	Id   int `layer:";pk"`
+	Name string
+}
+
+type GenUser struct {
+	Id        int64 `layer:";pk"`
+	Name      string
+	Nick      string `layer:"nick_name"`
+	Age       *int
+	Tags      []string `layer:";json"`
+	Dept      *GenDept `layer:";many2one"`
+	CreatedAt time.Time
+	*GenPoint `layer:";embedded=Pos"`
+	Ignore    string `layer:"-"`
+}
+
+func TestGenerate(t *testing.T) {
+	var b bytes.Buffer
+	err := Generate(&b, Config{Package: "gen", PkgPath: "github.com/meilihao/layer/gen"}, GenUser{})
+	assert.NoError(t, err)
+
+	// the generated file compiles
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "gen_layer.go", b.Bytes(), 0)
+	assert.NoError(t, err)
+	conf := types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+	_, err = conf.Check("github.com/meilihao/layer/gen", fset, []*ast.File{f}, nil)
+	assert.NoError(t, err)
+
+	// ignore the alignment of gofmt
+	src := strings.Join(strings.Fields(b.String()), " ")
+
+	for _, s := range []string{
+		"// Code generated by layergen. DO NOT EDIT.",
+		`time "time"`,
+		`const GenUserTable = "GenUser"`,
+		"Name genUserNameCol // name",
+		"NickName genUserNickNameCol // nick_name",
+		"DeptId genUserDeptIdCol // dept_id",
+		"PosX genUserPosXCol // pos_x",
+		`func (genUserAgeCol) Gt(v int) clause.Expression { return clause.Gt("Age", v) }`,
+		`func (genUserNickNameCol) Eq(v string) clause.Expression { return clause.Eq("nick_name", v) }`,
+		"func (genUserAgeCol) IsNull() clause.Expression",
+		"func (genUserNameCol) Like(v string) clause.Expression",
+		"func (genUserCreatedAtCol) Lte(v time.Time) clause.Expression",
+		"func (genUserDeptIdCol) In(vs ...int) clause.Expression",
+		"func (genUserTagsCol) Set(v interface{}) clause.Assignment",
+	} {
+		assert.Contains(t, src, s)
+	}
+
+	assert.NotContains(t, src, "Ignore")
+	assert.NotContains(t, src, "GenPoint")
+	assert.NotContains(t, src, "genUserTagsCol) Eq")
+	assert.NotContains(t, src, "genUserIdCol) Like")
+	assert.NotContains(t, src, "genUserIdCol) IsNull")
+
+	assert.Equal(t, ErrNoPackage, Generate(&b, Config{}, GenUser{}))
+}
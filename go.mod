@@ -3,6 +3,7 @@ module github.com/meilihao/layer
 go 1.15
 
 require (
+	github.com/mattn/go-sqlite3 v1.14.5
 	github.com/rs/zerolog v1.20.0
 	github.com/stretchr/testify v1.6.1
 )
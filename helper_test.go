@@ -0,0 +1,38 @@
+package layer
+
+import (
+	"path/filepath"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/meilihao/layer/dialect"
+)
+
+var (
+	pg   *Layer // postgres
+	lite *Layer // sqlite3
+)
+
+// init runs after the one of builder_update_test.go, which sets l
+func init() {
+	pg = withDialecter(dialect.NewDialecter("postgres", nil))
+	lite = withDialecter(dialect.NewDialecter("sqlite3", nil))
+}
+
+// withDialecter copy of l using d, for building statements without db
+func withDialecter(d dialect.Dialecter) *Layer {
+	return &Layer{opts: l.opts, dialecter: d}
+}
+
+// newSQLite layer of a new sqlite3 db file, for tests running statements
+func newSQLite(t *testing.T) *Layer {
+	sl, err := New(WithDB("sqlite3", filepath.Join(t.TempDir(), "test.db")), WithLogger(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		sl.Close()
+	})
+
+	return sl
+}
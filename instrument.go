@@ -0,0 +1,206 @@
+// Copyright (c) 2017 github.com/meilihao. All rights reserved.
+
+package layer
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Instrumenter receives start/end callbacks for every statement executed by layer,
+// it is the hook for tracing(span per query) and metrics(latency histogram).
+// Start returns the context used for the statement, so a span can be attached to it.
+type Instrumenter interface {
+	Start(ctx context.Context, ev *QueryEvent) context.Context
+	End(ctx context.Context, ev *QueryEvent)
+}
+
+// startQuery fill ev and call Instrumenter.Start, the returned context should be used to run the statement
+func (l *Layer) startQuery(ctx context.Context, ev *QueryEvent) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	ev.Dialect = l.dialecter.Dialect()
+	ev.start = time.Now()
+
+	if l.opts.instrumenter != nil {
+		l.fingerprint(ev)
+		ctx = l.opts.instrumenter.Start(ctx, ev)
+	}
+
+	return ctx
+}
+
+// fingerprint set ev.Fingerprint if not yet, it is only computed for Instrumenter and Logger
+func (l *Layer) fingerprint(ev *QueryEvent) {
+	if ev.Fingerprint == "" && ev.SQL != "" {
+		ev.Fingerprint = Fingerprint(l.dialecter, ev.SQL)
+	}
+}
+
+// endQuery translate ev.Err and set ev.Duration, then call Instrumenter.End and Logger
+func (l *Layer) endQuery(ctx context.Context, ev *QueryEvent, debug bool) {
+	ev.Err = l.TranslateError(ev.Err)
+
+	if !ev.start.IsZero() {
+		ev.Duration = time.Since(ev.start)
+	}
+
+	if l.opts.instrumenter != nil {
+		l.opts.instrumenter.End(ctx, ev)
+	}
+
+	l.logQuery(ctx, ev, debug)
+}
+
+// Stats returns database statistics of the underlying pool
+func (l *Layer) Stats() sql.DBStats {
+	if l.db == nil {
+		return sql.DBStats{}
+	}
+
+	return l.db.Stats()
+}
+
+// DefaultLatencyBuckets upper bounds of MemoryInstrumenter's latency histogram
+var DefaultLatencyBuckets = []time.Duration{
+	time.Millisecond,
+	5 * time.Millisecond,
+	10 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+	5 * time.Second,
+}
+
+// QueryStats aggregated metrics of one statement shape
+type QueryStats struct {
+	Op          Operation
+	Table       string
+	Dialect     string
+	Fingerprint string
+	SQL         string // first seen sql
+	Count       int64
+	Errors      int64
+	Rows        int64
+	Total       time.Duration
+	Max         time.Duration
+	Buckets     []time.Duration
+	Histogram   []int64 // Histogram[i] counts Duration <= Buckets[i], the last one is +Inf
+}
+
+// MemoryInstrumenter is an in-memory reference Instrumenter, it aggregates QueryStats by op, table and fingerprint.
+type MemoryInstrumenter struct {
+	mu      sync.Mutex
+	buckets []time.Duration
+	active  int64
+	stats   map[string]*QueryStats
+}
+
+var _ Instrumenter = (*MemoryInstrumenter)(nil)
+
+// NewMemoryInstrumenter use DefaultLatencyBuckets when buckets is empty
+func NewMemoryInstrumenter(buckets ...time.Duration) *MemoryInstrumenter {
+	if len(buckets) == 0 {
+		buckets = DefaultLatencyBuckets
+	}
+
+	bs := make([]time.Duration, len(buckets))
+	copy(bs, buckets)
+	sort.Slice(bs, func(i, j int) bool { return bs[i] < bs[j] })
+
+	return &MemoryInstrumenter{
+		buckets: bs,
+		stats:   make(map[string]*QueryStats),
+	}
+}
+
+func (m *MemoryInstrumenter) Start(ctx context.Context, ev *QueryEvent) context.Context {
+	m.mu.Lock()
+	m.active++
+	m.mu.Unlock()
+
+	return ctx
+}
+
+func (m *MemoryInstrumenter) End(ctx context.Context, ev *QueryEvent) {
+	key := string(ev.Op) + "\x00" + ev.Table + "\x00" + ev.Fingerprint
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.active--
+
+	s := m.stats[key]
+	if s == nil {
+		s = &QueryStats{
+			Op:          ev.Op,
+			Table:       ev.Table,
+			Dialect:     ev.Dialect,
+			Fingerprint: ev.Fingerprint,
+			SQL:         ev.SQL,
+			Buckets:     m.buckets,
+			Histogram:   make([]int64, len(m.buckets)+1),
+		}
+		m.stats[key] = s
+	}
+
+	s.Count++
+	s.Rows += ev.RowsAffected
+	s.Total += ev.Duration
+	if ev.Duration > s.Max {
+		s.Max = ev.Duration
+	}
+	if ev.Err != nil {
+		s.Errors++
+	}
+
+	i := sort.Search(len(m.buckets), func(i int) bool { return ev.Duration <= m.buckets[i] })
+	s.Histogram[i]++
+}
+
+// Active number of started but not ended statements
+func (m *MemoryInstrumenter) Active() int64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.active
+}
+
+// Snapshot copy of current QueryStats, sorted by Op, Table and Fingerprint
+func (m *MemoryInstrumenter) Snapshot() []QueryStats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	l := make([]QueryStats, 0, len(m.stats))
+	for _, s := range m.stats {
+		tmp := *s
+		tmp.Histogram = append([]int64(nil), s.Histogram...)
+
+		l = append(l, tmp)
+	}
+
+	sort.Slice(l, func(i, j int) bool {
+		if l[i].Op != l[j].Op {
+			return l[i].Op < l[j].Op
+		}
+		if l[i].Table != l[j].Table {
+			return l[i].Table < l[j].Table
+		}
+		return l[i].Fingerprint < l[j].Fingerprint
+	})
+
+	return l
+}
+
+// Reset drop all QueryStats
+func (m *MemoryInstrumenter) Reset() {
+	m.mu.Lock()
+	m.stats = make(map[string]*QueryStats)
+	m.mu.Unlock()
+}
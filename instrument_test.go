@@ -0,0 +1,92 @@
+package layer
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryInstrumenter(t *testing.T) {
+	m := NewMemoryInstrumenter(10*time.Millisecond, time.Millisecond)
+
+	tl := *l
+	tl.opts.instrumenter = m
+
+	for _, d := range []time.Duration{0, 5 * time.Millisecond, time.Second} {
+		ev := &QueryEvent{Op: OpFind, Table: "t", SQL: "SELECT * FROM `t` WHERE `id` = ?", RowsAffected: 1}
+		ctx := tl.startQuery(context.Background(), ev)
+		assert.EqualValues(t, 1, m.Active())
+
+		ev.start = ev.start.Add(-d)
+		tl.endQuery(ctx, ev, false)
+	}
+
+	ev := &QueryEvent{Op: OpCommit}
+	tl.endQuery(tl.startQuery(context.Background(), ev), ev, false)
+	ev = &QueryEvent{Op: OpFind, Table: "t", SQL: "SELECT * FROM `t` WHERE `id` = ?", Err: errors.New("x")}
+	tl.endQuery(tl.startQuery(context.Background(), ev), ev, false)
+
+	ss := m.Snapshot()
+	assert.EqualValues(t, 0, m.Active())
+	assert.EqualValues(t, 2, len(ss))
+	assert.EqualValues(t, OpCommit, ss[0].Op)
+
+	s := ss[1]
+	assert.EqualValues(t, "mysql", s.Dialect)
+	assert.EqualValues(t, Fingerprint(tl.dialecter, "SELECT * FROM `t` WHERE `id` = ?"), s.Fingerprint)
+	assert.EqualValues(t, 4, s.Count)
+	assert.EqualValues(t, 1, s.Errors)
+	assert.EqualValues(t, 3, s.Rows)
+	assert.EqualValues(t, []time.Duration{time.Millisecond, 10 * time.Millisecond}, s.Buckets)
+	assert.EqualValues(t, []int64{2, 1, 1}, s.Histogram)
+}
+
+func TestFingerprintLazy(t *testing.T) {
+	tl := *l
+	tl.opts.instrumenter = nil
+	tl.opts.logger = nil
+
+	ev := &QueryEvent{Op: OpFind, SQL: "SELECT 1"}
+	tl.endQuery(tl.startQuery(context.Background(), ev), ev, true)
+	assert.Empty(t, ev.Fingerprint)
+
+	tl.opts.instrumenter = NewMemoryInstrumenter()
+	ev = &QueryEvent{Op: OpFind, SQL: "SELECT 1"}
+	tl.endQuery(tl.startQuery(context.Background(), ev), ev, true)
+	assert.EqualValues(t, Fingerprint(tl.dialecter, "SELECT 1"), ev.Fingerprint)
+}
+
+// cancelInstrumenter cancels the context of op
+type cancelInstrumenter struct {
+	op Operation
+}
+
+func (i cancelInstrumenter) Start(ctx context.Context, ev *QueryEvent) context.Context {
+	if ev.Op == i.op {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithCancel(ctx)
+		cancel()
+	}
+
+	return ctx
+}
+
+func (cancelInstrumenter) End(ctx context.Context, ev *QueryEvent) {}
+
+func TestTransaction_Context(t *testing.T) {
+	tl := *newSQLite(t)
+
+	// begin runs with the context of Instrumenter.Start
+	tl.opts.instrumenter = cancelInstrumenter{op: OpBegin}
+	called := false
+	err := tl.Transaction(context.Background(), func(tx *sql.Tx) error {
+		called = true
+		return nil
+	}, nil)
+	assert.True(t, errors.Is(err, context.Canceled))
+	assert.False(t, called)
+}
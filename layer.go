@@ -5,6 +5,7 @@ package layer
 import (
 	"context"
 	"database/sql"
+	"fmt"
 
 	"github.com/meilihao/layer/dialect"
 	"github.com/meilihao/layer/schema"
@@ -14,15 +15,25 @@ import (
 type Layer struct {
 	opts      options
 	db        *sql.DB
+	tx        *sql.Tx // set by WithTx
 	dialecter dialect.Dialecter
 }
 
+// executor is implemented by *sql.DB and *sql.Tx
+type executor interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+	PrepareContext(ctx context.Context, query string) (*sql.Stmt, error)
+}
+
 // New init a new db connection, need to import driver first
 func New(opts ...optionFunc) (*Layer, error) {
 	options := options{
 		isShowSQL:  false,
 		nameMapper: schema.SnakeNameMapper{},
 		tz:         nil, // nil is time.Local
+		logger:     ZerologLogger{},
 	}
 
 	for _, o := range opts {
@@ -66,34 +77,95 @@ func New(opts ...optionFunc) (*Layer, error) {
 
 	l.dialecter = dialect.NewDialecter(l.opts.driverName, l.db)
 	if l.dialecter == nil {
-		panic(ErrUnsupportedDriverName)
+		if l.db != nil {
+			l.db.Close()
+		}
+
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedDriverName, l.opts.driverName)
 	}
 
 	return l, nil
 }
 
+// WithTx returns a copy of l which runs sessions, AQuery, Exec and bound *SQL in tx
+func (l *Layer) WithTx(tx *sql.Tx) *Layer {
+	n := *l
+	n.tx = tx
+
+	return &n
+}
+
+func (l *Layer) conn() executor {
+	if l.tx != nil {
+		return l.tx
+	}
+
+	return l.db
+}
+
 func (l *Layer) Dialect() dialect.Dialecter {
 	return l.dialecter
 }
 
+// TranslateError classify driver error by dialect, use errors.Is(err, ErrDuplicateKey) etc. to check it.
+// errors returned by layer are translated already, it is for errors from the raw *sql.Tx or *sql.DB.
+func (l *Layer) TranslateError(err error) error {
+	if err == nil || l.dialecter == nil {
+		return err
+	}
+
+	return l.dialecter.TranslateError(err)
+}
+
 func (l *Layer) Close() error {
 	return l.db.Close()
 }
 
 func (l *Layer) Exec(query string, args ...interface{}) (sql.Result, error) {
-	return l.db.Exec(query, args...)
+	query, args, err := l.rebind(query, args)
+	if err != nil {
+		return nil, err
+	}
+
+	return l.execContext(context.Background(), &QueryEvent{Op: OpExec, SQL: query, Args: args})
 }
 
 func (l *Layer) Query(query string, args ...interface{}) (*sql.Rows, error) {
-	return l.db.Query(query, args...)
+	query, args, err := l.rebind(query, args)
+	if err != nil {
+		return nil, err
+	}
+
+	ev := &QueryEvent{Op: OpQuery, SQL: query, Args: args}
+	ctx := l.startQuery(context.Background(), ev)
+
+	rows, err := l.conn().QueryContext(ctx, query, args...)
+	ev.Err = err
+	l.endQuery(ctx, ev, false)
+
+	return rows, ev.Err
 }
 
 func (l *Layer) QueryRow(query string, args ...interface{}) *sql.Row {
-	return l.db.QueryRow(query, args...)
+	ev := &QueryEvent{Op: OpQuery, SQL: query, Args: args}
+	ctx := l.startQuery(context.Background(), ev)
+
+	row := l.conn().QueryRowContext(ctx, query, args...)
+	ev.Err = row.Err()
+	l.endQuery(ctx, ev, false)
+
+	return row
 }
 
 func (l *Layer) Prepare(query string) (*sql.Stmt, error) {
-	return l.db.Prepare(query)
+	ev := &QueryEvent{Op: OpPrepare, SQL: query}
+	ctx := l.startQuery(context.Background(), ev)
+
+	stmt, err := l.conn().PrepareContext(ctx, query)
+	ev.Err = err
+	l.endQuery(ctx, ev, false)
+
+	return stmt, ev.Err
 }
 
 func (l *Layer) Begin() (*sql.Tx, error) {
@@ -105,18 +177,52 @@ func (l *Layer) BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, erro
 }
 
 func (l *Layer) AQuery(query string, args ...interface{}) *Rows {
+	query, args, err := l.rebind(query, args)
+	if err != nil {
+		return &Rows{err: err, l: l}
+	}
+
+	return l.queryContext(context.Background(), &QueryEvent{Op: OpQuery, SQL: query, Args: args})
+}
+
+// execContext exec ev.SQL with ev.Args
+func (l *Layer) execContext(ctx context.Context, ev *QueryEvent) (sql.Result, error) {
+	ctx = l.startQuery(ctx, ev)
+
+	r, err := l.conn().ExecContext(ctx, ev.SQL, ev.Args...)
+	if err == nil {
+		ev.RowsAffected, _ = r.RowsAffected()
+	}
+	ev.Err = err
+	l.endQuery(ctx, ev, false)
+
+	return r, ev.Err
+}
+
+// queryContext query ev.SQL with ev.Args
+func (l *Layer) queryContext(ctx context.Context, ev *QueryEvent) *Rows {
 	r := &Rows{
 		l: l,
 	}
 
-	r.rows, r.err = l.db.Query(query, args...)
+	ctx = l.startQuery(ctx, ev)
+
+	r.rows, r.err = l.conn().QueryContext(ctx, ev.SQL, ev.Args...)
+	ev.Err = r.err
+	l.endQuery(ctx, ev, false)
+	r.err = ev.Err
 
 	return r
 }
 
 // Transaction start a transaction as a block, return error will rollback, otherwise to commit.
 func (l *Layer) Transaction(ctx context.Context, fc func(tx *sql.Tx) error, opts *sql.TxOptions) (err error) {
-	tx, err := l.db.BeginTx(ctx, opts)
+	var tx *sql.Tx
+	err = l.traceTx(ctx, OpBegin, func(ctx context.Context) error {
+		var e error
+		tx, e = l.db.BeginTx(ctx, opts)
+		return e
+	})
 	if err != nil {
 		return
 	}
@@ -124,15 +230,34 @@ func (l *Layer) Transaction(ctx context.Context, fc func(tx *sql.Tx) error, opts
 	defer func() {
 		// Make sure to rollback when panic, Block error or Commit error
 		if err != nil {
-			tx.Rollback()
+			l.traceTx(ctx, OpRollback, func(context.Context) error {
+				if e := tx.Rollback(); e != sql.ErrTxDone {
+					return e
+				}
+				return nil
+			})
 		}
 	}()
 
-	err = fc(tx)
+	err = l.TranslateError(fc(tx))
 
 	if err == nil {
-		err = tx.Commit()
+		err = l.traceTx(ctx, OpCommit, func(context.Context) error {
+			return tx.Commit()
+		})
 	}
 
 	return
 }
+
+// traceTx report transaction begin/commit/rollback to Instrumenter and Logger,
+// fn runs with the context returned by Instrumenter.Start so the span wraps it
+func (l *Layer) traceTx(ctx context.Context, op Operation, fn func(ctx context.Context) error) error {
+	ev := &QueryEvent{Op: op}
+	ctx = l.startQuery(ctx, ev)
+
+	ev.Err = fn(ctx)
+	l.endQuery(ctx, ev, false)
+
+	return ev.Err
+}
@@ -0,0 +1,69 @@
+package layer
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLayer_Transaction(t *testing.T) {
+	sl := newSQLite(t)
+	ctx := context.Background()
+
+	_, err := sl.Exec("CREATE TABLE tx_user (id INTEGER PRIMARY KEY, name TEXT UNIQUE)")
+	assert.NoError(t, err)
+
+	// errors of the block are translated too
+	err = sl.Transaction(ctx, func(tx *sql.Tx) error {
+		if _, err := tx.Exec("INSERT INTO tx_user (name) VALUES ('a')"); err != nil {
+			return err
+		}
+		_, err := tx.Exec("INSERT INTO tx_user (name) VALUES ('a')")
+		return err
+	}, nil)
+	assert.True(t, errors.Is(err, ErrDuplicateKey))
+
+	// rolled back
+	var n int
+	_, err = sl.AQuery("SELECT count(*) FROM tx_user").One(&n)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 0, n)
+}
+
+func TestLayer_WithTx(t *testing.T) {
+	sl := newSQLite(t)
+	m := NewMemoryInstrumenter(time.Millisecond)
+	sl.opts.instrumenter = m
+
+	_, err := sl.Exec("CREATE TABLE tx_dept (id INTEGER PRIMARY KEY, name TEXT)")
+	assert.NoError(t, err)
+
+	tx, err := sl.Begin()
+	assert.NoError(t, err)
+	defer tx.Rollback()
+
+	_, err = tx.Exec("INSERT INTO tx_dept (name) VALUES ('a')")
+	assert.NoError(t, err)
+
+	// QueryRow and Prepare run in the bound transaction and are instrumented
+	tl := sl.WithTx(tx)
+	var n int
+	assert.NoError(t, tl.QueryRow("SELECT count(*) FROM tx_dept").Scan(&n))
+	assert.EqualValues(t, 1, n)
+
+	stmt, err := tl.Prepare("SELECT count(*) FROM tx_dept WHERE name = ?")
+	assert.NoError(t, err)
+	assert.NoError(t, stmt.QueryRow("a").Scan(&n))
+	assert.EqualValues(t, 1, n)
+	stmt.Close()
+
+	ops := map[Operation]int64{}
+	for _, s := range m.Snapshot() {
+		ops[s.Op] += s.Count
+	}
+	assert.EqualValues(t, map[Operation]int64{OpExec: 1, OpQuery: 1, OpPrepare: 1}, ops)
+}
@@ -0,0 +1,125 @@
+// Copyright (c) 2017 github.com/meilihao. All rights reserved.
+
+package layer
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+)
+
+// Operation is the kind of statement layer executed
+type Operation string
+
+const (
+	OpCreate  Operation = "create"
+	OpFind    Operation = "find"
+	OpUpdate  Operation = "update"
+	OpDelete  Operation = "delete"
+	OpQuery   Operation = "query"
+	OpExec    Operation = "exec"
+	OpPrepare Operation = "prepare"
+
+	OpBegin    Operation = "begin"
+	OpCommit   Operation = "commit"
+	OpRollback Operation = "rollback"
+)
+
+// LogLevel level of a QueryEvent
+type LogLevel int
+
+const (
+	LogInfo LogLevel = iota
+	LogWarn          // slow query
+	LogError
+)
+
+func (lv LogLevel) String() string {
+	switch lv {
+	case LogWarn:
+		return "warn"
+	case LogError:
+		return "error"
+	default:
+		return "info"
+	}
+}
+
+// QueryEvent describes one executed statement
+type QueryEvent struct {
+	Op           Operation
+	Table        string
+	Dialect      string
+	SQL          string
+	Fingerprint  string // statement shape for aggregation, see Fingerprint()
+	Args         []interface{}
+	Explain      string // SQL with args interpolated by Dialecter.Explain, only set by WithLogExplain
+	Duration     time.Duration
+	RowsAffected int64
+	Err          error
+
+	start time.Time
+}
+
+// Logger receives structured QueryEvent
+type Logger interface {
+	Log(ctx context.Context, level LogLevel, ev *QueryEvent)
+}
+
+// ZerologLogger is the default Logger, it writes to zerolog's global logger
+type ZerologLogger struct{}
+
+func (ZerologLogger) Log(ctx context.Context, level LogLevel, ev *QueryEvent) {
+	var e *zerolog.Event
+	switch level {
+	case LogWarn:
+		e = log.Warn().Bool("slow", true)
+	case LogError:
+		e = log.Error().Err(ev.Err)
+	default:
+		e = log.Info()
+	}
+
+	e = e.Str("op", string(ev.Op)).
+		Str("table", ev.Table).
+		Str("fingerprint", ev.Fingerprint).
+		Dur("duration", ev.Duration).
+		Int64("rows", ev.RowsAffected)
+
+	if ev.Explain != "" {
+		e.Msg(ev.Explain)
+	} else {
+		e.Interface("args", ev.Args).Msg(ev.SQL)
+	}
+}
+
+// logQuery send ev to Logger.
+// errors and slow queries are always logged, others only with debug or WithShowSQL
+func (l *Layer) logQuery(ctx context.Context, ev *QueryEvent, debug bool) {
+	if l.opts.logger == nil {
+		return
+	}
+
+	level := LogInfo
+	switch {
+	case ev.Err != nil:
+		level = LogError
+	case l.opts.slowThreshold > 0 && ev.Duration >= l.opts.slowThreshold:
+		level = LogWarn
+	case !debug && !l.opts.isShowSQL:
+		return
+	}
+
+	l.fingerprint(ev)
+	if l.opts.logExplain {
+		ev.Explain = l.dialecter.Explain(ev.SQL, ev.Args)
+	}
+
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	l.opts.logger.Log(ctx, level, ev)
+}
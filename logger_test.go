@@ -0,0 +1,70 @@
+package layer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/meilihao/layer/dialect"
+	"github.com/meilihao/layer/schema"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/assert"
+)
+
+type memLogger struct {
+	levels []LogLevel
+	events []*QueryEvent
+}
+
+func (m *memLogger) Log(ctx context.Context, level LogLevel, ev *QueryEvent) {
+	m.levels = append(m.levels, level)
+	m.events = append(m.events, ev)
+}
+
+func TestLogQuery(t *testing.T) {
+	ml := &memLogger{}
+	tl := &Layer{
+		opts: options{
+			nameMapper:    schema.SnakeNameMapper{},
+			logger:        ml,
+			slowThreshold: 100 * time.Millisecond,
+			logExplain:    true,
+		},
+		dialecter: dialect.NewDialecter("mysql", nil),
+	}
+
+	// fast query without debug/showSQL is not logged
+	tl.logQuery(nil, &QueryEvent{Op: OpFind, SQL: "SELECT 1", Duration: time.Millisecond}, false)
+	assert.EqualValues(t, 0, len(ml.events))
+
+	tl.logQuery(nil, &QueryEvent{Op: OpFind, SQL: "SELECT `a` FROM `t` WHERE `id` = ?", Args: []interface{}{1},
+		Duration: time.Millisecond}, true)
+	tl.logQuery(nil, &QueryEvent{Op: OpUpdate, SQL: "UPDATE `t` SET `a`=?", Args: []interface{}{1},
+		Duration: time.Second}, false)
+	tl.logQuery(nil, &QueryEvent{Op: OpDelete, SQL: "DELETE FROM `t`", Err: errors.New("x")}, false)
+
+	assert.EqualValues(t, []LogLevel{LogInfo, LogWarn, LogError}, ml.levels)
+	assert.EqualValues(t, "SELECT `a` FROM `t` WHERE `id` = 1", ml.events[0].Explain)
+}
+
+func TestZerologLogger(t *testing.T) {
+	var buf bytes.Buffer
+	old := log.Logger
+	log.Logger = zerolog.New(&buf)
+	defer func() { log.Logger = old }()
+
+	tl := *l
+	tl.opts.logger = ZerologLogger{}
+	tl.logQuery(nil, &QueryEvent{Op: OpFind, Table: "t", SQL: "SELECT `a` FROM `t` WHERE `id` = ?", Args: []interface{}{1}}, true)
+
+	m := map[string]interface{}{}
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &m))
+	assert.EqualValues(t, "find", m["op"])
+	assert.EqualValues(t, "t", m["table"])
+	assert.EqualValues(t, Fingerprint(tl.dialecter, "SELECT `a` FROM `t` WHERE `id` = ?"), m["fingerprint"])
+	assert.EqualValues(t, "SELECT `a` FROM `t` WHERE `id` = ?", m["message"])
+}
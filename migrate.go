@@ -0,0 +1,458 @@
+package layer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/meilihao/layer/clause"
+	"github.com/meilihao/layer/dialect"
+	"github.com/meilihao/layer/schema"
+)
+
+var (
+	ErrUnsupportedAlter = errors.New("layer : unsupported alter")
+)
+
+// MigrateOption option of Migrate
+type MigrateOption func(*migrator)
+
+// MigrateSafe never drop columns, nor the indexes and constraints on them
+func MigrateSafe() MigrateOption {
+	return func(m *migrator) {
+		m.safe = true
+	}
+}
+
+// MigrateDryRun write the plan to w instead of executing it
+func MigrateDryRun(w io.Writer) MigrateOption {
+	return func(m *migrator) {
+		m.dryRun = w
+	}
+}
+
+// MigrateNoForeignKey neither add nor drop foreign keys
+func MigrateNoForeignKey() MigrateOption {
+	return func(m *migrator) {
+		m.noForeignKey = true
+	}
+}
+
+type migrator struct {
+	l            *Layer
+	m            dialect.Migrator
+	safe         bool
+	dryRun       io.Writer
+	noForeignKey bool
+}
+
+func (l *Layer) newMigrator(opts []MigrateOption) (*migrator, error) {
+	m, ok := l.dialecter.(dialect.Migrator)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedDDL, l.dialecter.Dialect())
+	}
+
+	mg := &migrator{l: l, m: m}
+	for _, opt := range opts {
+		opt(mg)
+	}
+
+	return mg, nil
+}
+
+// MigratePlan statements which make tables in db the same as models, in order:
+// CREATE TABLE of missing tables, then for each table DROP FOREIGN KEY, DROP INDEX, ADD COLUMN, ALTER COLUMN and DROP COLUMN,
+// at last CREATE INDEX and ADD FOREIGN KEY of all tables. changes of primary keys are not supported.
+func (l *Layer) MigratePlan(ctx context.Context, models []interface{}, opts ...MigrateOption) ([]string, error) {
+	m, err := l.newMigrator(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return m.plan(ctx, models)
+}
+
+// Migrate execute MigratePlan, the plan is only written to the writer of MigrateDryRun in dry-run mode
+func (l *Layer) Migrate(ctx context.Context, models []interface{}, opts ...MigrateOption) error {
+	m, err := l.newMigrator(opts)
+	if err != nil {
+		return err
+	}
+
+	stmts, err := m.plan(ctx, models)
+	if err != nil {
+		return err
+	}
+
+	for _, stmt := range stmts {
+		if m.dryRun != nil {
+			if _, err = fmt.Fprintf(m.dryRun, "%s;\n", stmt); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		if _, err = l.execContext(ctx, &QueryEvent{Op: OpExec, SQL: stmt}); err != nil {
+			return l.TranslateError(err)
+		}
+	}
+
+	return nil
+}
+
+func (m *migrator) plan(ctx context.Context, models []interface{}) ([]string, error) {
+	var pre, post []string
+	for _, model := range models {
+		sc, err := schema.Parse(model, m.l.opts.nameMapper)
+		if err != nil {
+			return nil, err
+		}
+
+		b := &ddlBuilder{l: m.l, d: m.m, sc: sc, noForeignKey: m.noForeignKey}
+
+		t, err := m.m.Table(ctx, sc.TableSchema, sc.DBName)
+		if err != nil {
+			return nil, err
+		}
+
+		if t == nil {
+			stmts, err := b.build()
+			if err != nil {
+				return nil, err
+			}
+
+			pre = append(pre, stmts...)
+
+			continue
+		}
+
+		p, q, err := m.diff(b, t)
+		if err != nil {
+			return nil, err
+		}
+
+		pre = append(pre, p...)
+		post = append(post, q...)
+	}
+
+	return append(pre, post...), nil
+}
+
+// diff statements changing t to b.sc, post are the ones after all tables are changed
+func (m *migrator) diff(b *ddlBuilder, t *dialect.TableInfo) (pre, post []string, err error) {
+	style := m.m.Alter()
+	unsupported := func(format string, args ...interface{}) error {
+		return fmt.Errorf("%w in %s: %s", ErrUnsupportedAlter, m.l.dialecter.Dialect(), fmt.Sprintf(format, args...))
+	}
+
+	if !sameColumns(b.sc.PrimaryColumns, t.PrimaryKey) {
+		pk := make([]string, len(b.sc.PrimaryColumns))
+		for i, c := range b.sc.PrimaryColumns {
+			pk[i] = c.DBName
+		}
+
+		return nil, nil, unsupported("change primary key of %s from (%s) to (%s)", t.Name, strings.Join(t.PrimaryKey, ","), strings.Join(pk, ","))
+	}
+
+	// foreign keys
+	var dropFKs, addFKs []string
+	fkColumns := map[string]bool{}
+	if !m.noForeignKey {
+		matched := map[*dialect.ForeignKeyInfo]bool{}
+		for _, fk := range b.foreignKeys() {
+			if e := findForeignKey(t, fk); e != nil {
+				matched[e] = true
+				continue
+			}
+			if style == dialect.AlterLimited {
+				return nil, nil, unsupported("add foreign key %s", fk.name)
+			}
+
+			sb := m.alterTable(b)
+			sb.WriteString(" ADD ")
+			b.writeForeignKey(sb, fk)
+			addFKs = append(addFKs, sb.String())
+		}
+
+		for _, e := range t.ForeignKeys {
+			fkColumns[strings.ToLower(strings.Join(e.Columns, ","))] = true
+			if matched[e] || (m.safe && hasLegacyColumn(b.sc, e.Columns)) {
+				continue
+			}
+			if style == dialect.AlterLimited {
+				return nil, nil, unsupported("drop foreign key of %s", strings.Join(e.Columns, ","))
+			}
+
+			sb := m.alterTable(b)
+			if style == dialect.AlterModify {
+				sb.WriteString(" DROP FOREIGN KEY ")
+			} else {
+				sb.WriteString(" DROP CONSTRAINT ")
+			}
+			sb.WriteString(m.l.dialecter.Queto(e.Name))
+			dropFKs = append(dropFKs, sb.String())
+		}
+	}
+
+	// indexes, unique column is a unique index named uk_<table>_<column>
+	indexes := b.indexes()
+	for _, c := range b.sc.Columns {
+		if isUniqueColumn(c) {
+			indexes = append(indexes, &ddlIndex{name: "uk_" + b.sc.DBName + "_" + c.DBName, unique: true, columns: []*schema.Column{c}})
+		}
+	}
+
+	var dropIndexes, createIndexes []string
+	matched := map[*dialect.IndexInfo]bool{}
+	for _, idx := range indexes {
+		if e := findIndex(t, idx); e != nil {
+			matched[e] = true
+			continue
+		}
+
+		createIndexes = append(createIndexes, b.createIndex(idx))
+	}
+	for _, e := range t.Indexes {
+		// index created by mysql for foreign key
+		if matched[e] || (!e.Unique && fkColumns[strings.ToLower(strings.Join(e.Columns, ","))]) {
+			continue
+		}
+		if m.safe && hasLegacyColumn(b.sc, e.Columns) {
+			continue
+		}
+
+		sb := NewSQLBuilder(m.l, nil, 64)
+		switch {
+		case e.Constraint && style == dialect.AlterLimited:
+			return nil, nil, unsupported("drop unique constraint of %s", strings.Join(e.Columns, ","))
+		case e.Constraint && style == dialect.AlterStandard:
+			sb = m.alterTable(b)
+			sb.WriteString(" DROP CONSTRAINT " + m.l.dialecter.Queto(e.Name))
+		default:
+			sb.WriteString("DROP INDEX ")
+			if style != dialect.AlterModify && b.sc.TableSchema != "" {
+				sb.WriteString(m.l.dialecter.Queto(b.sc.TableSchema) + ".")
+			}
+			sb.WriteString(m.l.dialecter.Queto(e.Name))
+			if style == dialect.AlterModify {
+				sb.WriteString(" ON ")
+				sb.WriteQuoted(b.table())
+			}
+		}
+		dropIndexes = append(dropIndexes, sb.String())
+	}
+
+	// columns
+	var adds, alters, drops []string
+	for _, c := range b.sc.Columns {
+		e := t.Column(c.DBName)
+		if e == nil {
+			// rows have no value for the new column
+			if f := columnField(c); f.NotNull || c.IsPK {
+				if _, ok := b.columnDefault(c); !ok {
+					return nil, nil, unsupported("add not null column %s without default", c.DBName)
+				}
+			}
+
+			sb := m.alterTable(b)
+			sb.WriteString(" ADD COLUMN ")
+			if _, err = b.writeColumn(sb, c, false); err != nil {
+				return nil, nil, err
+			}
+			adds = append(adds, sb.String())
+
+			if f := columnField(c); f.Comment != "" && m.m.Comment() == dialect.CommentOn {
+				alters = append(alters, b.commentOn(c, f.Comment))
+			}
+
+			continue
+		}
+
+		stmts, err := m.alterColumn(b, c, e)
+		if err != nil {
+			return nil, nil, err
+		}
+		alters = append(alters, stmts...)
+	}
+
+	if !m.safe {
+		for _, e := range t.Columns {
+			if findColumn(b.sc, e.Name) != nil {
+				continue
+			}
+
+			sb := m.alterTable(b)
+			sb.WriteString(" DROP COLUMN " + m.l.dialecter.Queto(e.Name))
+			drops = append(drops, sb.String())
+		}
+	}
+
+	pre = append(append(append(append(dropFKs, dropIndexes...), adds...), alters...), drops...)
+	post = append(createIndexes, addFKs...)
+
+	return pre, post, nil
+}
+
+// alterColumn statements changing type, not null and comment of e to c
+func (m *migrator) alterColumn(b *ddlBuilder, c *schema.Column, e *dialect.ColumnInfo) ([]string, error) {
+	f := columnField(c)
+
+	typ, err := b.columnType(c, c.IsPK && c.Field.AutoIncr)
+	if err != nil {
+		return nil, err
+	}
+
+	typeChanged := m.m.NormalizeType(typ) != m.m.NormalizeType(e.Type)
+	notNull := f.NotNull || c.IsPK
+	notNullChanged := notNull != e.NotNull
+	commentChanged := m.m.Comment() != dialect.CommentUnsupported && f.Comment != e.Comment
+
+	// default of serial and auto increment is not compared
+	def, hasDef := b.columnDefault(c)
+	defaultChanged := false
+	if !(c.IsPK && c.Field.AutoIncr) {
+		old, hasOld := e.Default.String, e.Default.Valid && !strings.EqualFold(e.Default.String, "null")
+		defaultChanged = hasDef != hasOld || hasDef && !sameDefault(m.m.NormalizeDefault(def), m.m.NormalizeDefault(old))
+	}
+
+	var stmts []string
+	switch m.m.Alter() {
+	case dialect.AlterModify:
+		if typeChanged || notNullChanged || commentChanged || defaultChanged {
+			sb := m.alterTable(b)
+			sb.WriteString(" MODIFY COLUMN ")
+			if _, err = b.writeColumn(sb, c, false); err != nil {
+				return nil, err
+			}
+			stmts = append(stmts, sb.String())
+		}
+	case dialect.AlterStandard:
+		if typeChanged {
+			// serial types are only for CREATE TABLE
+			typ, _ = b.columnType(c, false)
+
+			sb := m.alterTable(b)
+			sb.WriteString(" ALTER COLUMN ")
+			sb.WriteQuoted(clause.Column{Name: c.RawName})
+			sb.WriteString(" TYPE " + typ)
+			stmts = append(stmts, sb.String())
+		}
+		if notNullChanged {
+			sb := m.alterTable(b)
+			sb.WriteString(" ALTER COLUMN ")
+			sb.WriteQuoted(clause.Column{Name: c.RawName})
+			if notNull {
+				sb.WriteString(" SET NOT NULL")
+			} else {
+				sb.WriteString(" DROP NOT NULL")
+			}
+			stmts = append(stmts, sb.String())
+		}
+		if defaultChanged {
+			sb := m.alterTable(b)
+			sb.WriteString(" ALTER COLUMN ")
+			sb.WriteQuoted(clause.Column{Name: c.RawName})
+			if hasDef {
+				sb.WriteString(" SET DEFAULT " + def)
+			} else {
+				sb.WriteString(" DROP DEFAULT")
+			}
+			stmts = append(stmts, sb.String())
+		}
+		if commentChanged {
+			stmts = append(stmts, b.commentOn(c, f.Comment))
+		}
+	default:
+		if typeChanged || notNullChanged {
+			return nil, fmt.Errorf("%w in %s: change column %s from %s to %s", ErrUnsupportedAlter,
+				m.l.dialecter.Dialect(), c.DBName, e.Type, typ)
+		}
+		if defaultChanged {
+			return nil, fmt.Errorf("%w in %s: change default of column %s from %s to %s", ErrUnsupportedAlter,
+				m.l.dialecter.Dialect(), c.DBName, e.Default.String, def)
+		}
+	}
+
+	return stmts, nil
+}
+
+// sameDefault numbers are compared by value, since db may change their format like 0 to 0.00
+func sameDefault(a, b string) bool {
+	if a == b {
+		return true
+	}
+
+	x, err1 := strconv.ParseFloat(a, 64)
+	y, err2 := strconv.ParseFloat(b, 64)
+
+	return err1 == nil && err2 == nil && x == y
+}
+
+func (m *migrator) alterTable(b *ddlBuilder) *SQLBuilder {
+	sb := NewSQLBuilder(m.l, nil, 64)
+	sb.WriteString("ALTER TABLE ")
+	sb.WriteQuoted(b.table())
+
+	return sb
+}
+
+func findColumn(sc *schema.Schema, dbName string) *schema.Column {
+	for _, c := range sc.Columns {
+		if strings.EqualFold(c.DBName, dbName) {
+			return c
+		}
+	}
+
+	return nil
+}
+
+// hasLegacyColumn whether any of cols is not in sc, MigrateSafe keeps such a column with its indexes and constraints
+func hasLegacyColumn(sc *schema.Schema, cols []string) bool {
+	for _, c := range cols {
+		if findColumn(sc, c) == nil {
+			return true
+		}
+	}
+
+	return false
+}
+
+func sameColumns(cols []*schema.Column, names []string) bool {
+	if len(cols) != len(names) {
+		return false
+	}
+	for i, c := range cols {
+		if !strings.EqualFold(c.DBName, names[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// findIndex index of t with the same columns and uniqueness, names are not compared
+func findIndex(t *dialect.TableInfo, idx *ddlIndex) *dialect.IndexInfo {
+	for _, e := range t.Indexes {
+		if e.Unique == idx.unique && sameColumns(idx.columns, e.Columns) {
+			return e
+		}
+	}
+
+	return nil
+}
+
+// findForeignKey foreign key of t with the same columns and references, names are not compared
+func findForeignKey(t *dialect.TableInfo, fk *ddlForeignKey) *dialect.ForeignKeyInfo {
+	for _, e := range t.ForeignKeys {
+		if strings.EqualFold(e.RefTable, fk.parent.DBName) && sameColumns(fk.columns, e.Columns) &&
+			sameColumns(fk.parent.PrimaryColumns, e.RefColumns) {
+			return e
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,260 @@
+package layer
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/meilihao/layer/dialect"
+	"github.com/stretchr/testify/assert"
+)
+
+type migratorDialecter interface {
+	dialect.Dialecter
+	dialect.Migrator
+}
+
+// testMigrator reads tables from memory
+type testMigrator struct {
+	migratorDialecter
+	tables map[string]*dialect.TableInfo
+}
+
+func (m testMigrator) Table(ctx context.Context, schema, name string) (*dialect.TableInfo, error) {
+	return m.tables[name], nil
+}
+
+func TestLayer_MigrateMySQL(t *testing.T) {
+	user := &dialect.TableInfo{
+		Name: "ddl_user",
+		Columns: []*dialect.ColumnInfo{
+			{Name: "id", Type: "bigint(20)", NotNull: true},
+			{Name: "email", Type: "varchar(128)", NotNull: true},
+			{Name: "age", Type: "tinyint(3) unsigned", Default: sql.NullString{String: "20", Valid: true}},
+			{Name: "score", Type: "decimal(10,2)", Default: sql.NullString{String: "0.00", Valid: true}},
+			{Name: "status", Type: "varchar(8)"},
+			{Name: "tags", Type: "json"},
+			{Name: "dept_id", Type: "bigint", NotNull: true, Comment: "dept"},
+			{Name: "addr_city", Type: "varchar(32)"},
+			{Name: "addr_street", Type: "varchar(255)"},
+			{Name: "version", Type: "bigint"},
+			{Name: "created_at", Type: "datetime(3)", Default: sql.NullString{String: "CURRENT_TIMESTAMP(3)", Valid: true}},
+			{Name: "legacy", Type: "int(11)"},
+		},
+		PrimaryKey: []string{"id"},
+		Indexes: []*dialect.IndexInfo{
+			{Name: "email", Unique: true, Columns: []string{"email"}},
+			{Name: "idx_ddl_user_status", Columns: []string{"status"}},
+			{Name: "idx_legacy", Columns: []string{"legacy"}},
+		},
+	}
+	ml := withDialecter(testMigrator{dialect.MySQLDialecter, map[string]*dialect.TableInfo{"ddl_user": user}})
+
+	stmts, err := ml.MigratePlan(context.Background(), []interface{}{&ddlDept{}, &ddlUser{}})
+	assert.NoError(t, err)
+	assert.EqualValues(t, []string{
+		"CREATE TABLE `ddl_dept` (`id` BIGINT NOT NULL AUTO_INCREMENT,`name` VARCHAR(64) NOT NULL UNIQUE COMMENT 'dept''s name',PRIMARY KEY (`id`))",
+		"DROP INDEX `idx_legacy` ON `ddl_user`",
+		"ALTER TABLE `ddl_user` ADD COLUMN `avatar` LONGBLOB",
+		"ALTER TABLE `ddl_user` MODIFY COLUMN `age` TINYINT UNSIGNED DEFAULT 18",
+		"ALTER TABLE `ddl_user` MODIFY COLUMN `score` DECIMAL(10,2)",
+		"ALTER TABLE `ddl_user` MODIFY COLUMN `status` VARCHAR(16) DEFAULT 'active'",
+		"ALTER TABLE `ddl_user` DROP COLUMN `legacy`",
+		"CREATE INDEX `idx_ddl_user_addr` ON `ddl_user` (`addr_city`,`addr_street`)",
+		"ALTER TABLE `ddl_user` ADD CONSTRAINT `fk_ddl_user_dept` FOREIGN KEY (`dept_id`) REFERENCES `ddl_dept` (`id`)",
+	}, stmts)
+
+	// safe mode keeps legacy and its index
+	user.Columns[2].Default.String = "18"
+	user.Columns[3].Default.Valid = false
+	stmts, err = ml.MigratePlan(context.Background(), []interface{}{&ddlUser{}}, MigrateSafe(), MigrateNoForeignKey())
+	assert.NoError(t, err)
+	assert.EqualValues(t, []string{
+		"ALTER TABLE `ddl_user` ADD COLUMN `avatar` LONGBLOB",
+		"ALTER TABLE `ddl_user` MODIFY COLUMN `status` VARCHAR(16) DEFAULT 'active'",
+		"CREATE INDEX `idx_ddl_user_addr` ON `ddl_user` (`addr_city`,`addr_street`)",
+	}, stmts)
+
+	// dry run prints the plan without db
+	var buf bytes.Buffer
+	err = ml.Migrate(context.Background(), []interface{}{&ddlUser{}}, MigrateSafe(), MigrateNoForeignKey(), MigrateDryRun(&buf))
+	assert.NoError(t, err)
+	assert.EqualValues(t, "ALTER TABLE `ddl_user` ADD COLUMN `avatar` LONGBLOB;\n"+
+		"ALTER TABLE `ddl_user` MODIFY COLUMN `status` VARCHAR(16) DEFAULT 'active';\n"+
+		"CREATE INDEX `idx_ddl_user_addr` ON `ddl_user` (`addr_city`,`addr_street`);\n", buf.String())
+
+	// safe mode keeps the foreign key of legacy too
+	user.ForeignKeys = []*dialect.ForeignKeyInfo{{Name: "fk_legacy", Columns: []string{"legacy"}, RefTable: "ddl_dept", RefColumns: []string{"id"}}}
+	stmts, err = ml.MigratePlan(context.Background(), []interface{}{&ddlUser{}}, MigrateSafe())
+	assert.NoError(t, err)
+	assert.EqualValues(t, []string{
+		"ALTER TABLE `ddl_user` ADD COLUMN `avatar` LONGBLOB",
+		"ALTER TABLE `ddl_user` MODIFY COLUMN `status` VARCHAR(16) DEFAULT 'active'",
+		"CREATE INDEX `idx_ddl_user_addr` ON `ddl_user` (`addr_city`,`addr_street`)",
+		"ALTER TABLE `ddl_user` ADD CONSTRAINT `fk_ddl_user_dept` FOREIGN KEY (`dept_id`) REFERENCES `ddl_dept` (`id`)",
+	}, stmts)
+}
+
+func TestLayer_MigratePostgres(t *testing.T) {
+	dept := &dialect.TableInfo{
+		Name: "ddl_dept",
+		Columns: []*dialect.ColumnInfo{
+			{Name: "id", Type: "bigint", NotNull: true, Default: sql.NullString{String: "nextval('ddl_dept_id_seq'::regclass)", Valid: true}},
+			{Name: "name", Type: "character varying(64)", Comment: "dept"},
+		},
+		PrimaryKey: []string{"id"},
+		Indexes: []*dialect.IndexInfo{
+			{Name: "ddl_dept_name_key", Unique: true, Constraint: true, Columns: []string{"name"}},
+			{Name: "ddl_dept_name_idx", Unique: true, Constraint: true, Columns: []string{"name", "id"}},
+		},
+	}
+	user := &dialect.TableInfo{
+		Name: "ddl_user",
+		Columns: []*dialect.ColumnInfo{
+			{Name: "id", Type: "bigint", NotNull: true},
+			{Name: "email", Type: "character varying(128)", NotNull: true},
+			{Name: "status", Type: "character varying(16)", Default: sql.NullString{String: "'active'::character varying", Valid: true}},
+			{Name: "age", Type: "smallint", Default: sql.NullString{String: "(-1)", Valid: true}},
+			{Name: "dept_id", Type: "integer"},
+		},
+		PrimaryKey: []string{"id"},
+		ForeignKeys: []*dialect.ForeignKeyInfo{
+			{Name: "ddl_user_dept_id_fkey", Columns: []string{"dept_id"}, RefTable: "ddl_dept", RefColumns: []string{"id"}},
+		},
+	}
+	pl := withDialecter(testMigrator{dialect.PostgresDialecter, map[string]*dialect.TableInfo{"ddl_dept": dept, "ddl_user": user}})
+
+	stmts, err := pl.MigratePlan(context.Background(), []interface{}{&ddlDept{}})
+	assert.NoError(t, err)
+	assert.EqualValues(t, []string{
+		`ALTER TABLE "ddl_dept" DROP CONSTRAINT "ddl_dept_name_idx"`,
+		`ALTER TABLE "ddl_dept" ALTER COLUMN "name" SET NOT NULL`,
+		`COMMENT ON COLUMN "ddl_dept"."name" IS 'dept''s name'`,
+	}, stmts)
+
+	stmts, err = pl.MigratePlan(context.Background(), []interface{}{&ddlUser{}}, MigrateSafe())
+	assert.NoError(t, err)
+	// missing columns are added before changes of existing ones, status has the same default
+	assert.EqualValues(t, []string{
+		`ALTER TABLE "ddl_user" ADD COLUMN "score" NUMERIC(10,2)`,
+		`ALTER TABLE "ddl_user" ADD COLUMN "tags" JSONB`,
+		`ALTER TABLE "ddl_user" ADD COLUMN "addr_city" VARCHAR(32)`,
+		`ALTER TABLE "ddl_user" ADD COLUMN "addr_street" TEXT`,
+		`ALTER TABLE "ddl_user" ADD COLUMN "avatar" BYTEA`,
+		`ALTER TABLE "ddl_user" ADD COLUMN "version" BIGINT`,
+		`ALTER TABLE "ddl_user" ADD COLUMN "created_at" TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP`,
+		`ALTER TABLE "ddl_user" ALTER COLUMN "age" SET DEFAULT 18`,
+		`ALTER TABLE "ddl_user" ALTER COLUMN "dept_id" TYPE BIGINT`,
+		`ALTER TABLE "ddl_user" ALTER COLUMN "dept_id" SET NOT NULL`,
+		`COMMENT ON COLUMN "ddl_user"."dept_id" IS 'dept'`,
+		`CREATE UNIQUE INDEX "uk_ddl_user_email" ON "ddl_user" ("email")`,
+		`CREATE INDEX "idx_ddl_user_status" ON "ddl_user" ("status")`,
+		`CREATE INDEX "idx_ddl_user_addr" ON "ddl_user" ("addr_city","addr_street")`,
+	}, stmts)
+
+	// existing rows have no value for a not null column without default
+	user.Columns = append(user.Columns[:1], user.Columns[2:]...)
+	_, err = pl.MigratePlan(context.Background(), []interface{}{&ddlUser{}}, MigrateSafe())
+	assert.True(t, errors.Is(err, ErrUnsupportedAlter))
+	assert.EqualValues(t, "layer : unsupported alter in postgres: add not null column email without default", err.Error())
+
+	user.PrimaryKey = nil
+	_, err = pl.MigratePlan(context.Background(), []interface{}{&ddlUser{}}, MigrateSafe())
+	assert.EqualValues(t, "layer : unsupported alter in postgres: change primary key of ddl_user from () to (id)", err.Error())
+}
+
+func TestLayer_MigrateSQLite(t *testing.T) {
+	dept := &dialect.TableInfo{
+		Name: "ddl_dept",
+		Columns: []*dialect.ColumnInfo{
+			{Name: "id", Type: "INTEGER", NotNull: true},
+		},
+		PrimaryKey: []string{"id"},
+	}
+	sl := withDialecter(testMigrator{dialect.SQLiteDialecter, map[string]*dialect.TableInfo{"ddl_dept": dept}})
+
+	// sqlite can not add a not null column without default
+	_, err := sl.MigratePlan(context.Background(), []interface{}{&ddlDept{}})
+	assert.True(t, errors.Is(err, ErrUnsupportedAlter))
+
+	dept.Columns = append(dept.Columns, &dialect.ColumnInfo{Name: "name", Type: "VARCHAR(64)", NotNull: true})
+	stmts, err := sl.MigratePlan(context.Background(), []interface{}{&ddlDept{}})
+	assert.NoError(t, err)
+	assert.EqualValues(t, []string{
+		`CREATE UNIQUE INDEX "uk_ddl_dept_name" ON "ddl_dept" ("name")`,
+	}, stmts)
+
+	dept.Columns[1].Default = sql.NullString{String: "'x'", Valid: true}
+	_, err = sl.MigratePlan(context.Background(), []interface{}{&ddlDept{}})
+	assert.True(t, errors.Is(err, ErrUnsupportedAlter))
+
+	dept.Columns[1].Default.Valid = false
+	dept.Columns[0].Type = "BIGINT"
+	_, err = sl.MigratePlan(context.Background(), []interface{}{&ddlDept{}})
+	assert.True(t, errors.Is(err, ErrUnsupportedAlter))
+
+	err = l.Migrate(context.Background(), []interface{}{&ddlDept{}}, MigrateDryRun(&bytes.Buffer{}))
+	assert.True(t, errors.Is(err, dialect.ErrNoDB))
+}
+
+// ddlDeptV2 ddlDept with a new column
+type ddlDeptV2 struct {
+	Id   int64  `layer:";pk;autoincr"`
+	Name string `layer:";size=64;notnull;unique;comment=dept's name"`
+	Code string `layer:";size=8;notnull;default=x;index"`
+}
+
+func (ddlDeptV2) TableName() string {
+	return "ddl_dept"
+}
+
+func TestLayer_MigrateSQLiteDB(t *testing.T) {
+	sl := newSQLite(t)
+	ctx := context.Background()
+	m := sl.dialecter.(dialect.Migrator)
+
+	models := []interface{}{&ddlDept{}, &ddlUser{}}
+	assert.NoError(t, sl.CreateTable(ctx, models))
+
+	user, err := m.Table(ctx, "", "ddl_user")
+	assert.NoError(t, err)
+	assert.EqualValues(t, []string{"id"}, user.PrimaryKey)
+	assert.EqualValues(t, &dialect.ColumnInfo{Name: "email", Type: "VARCHAR(128)", NotNull: true}, user.Column("email"))
+	assert.EqualValues(t, sql.NullString{String: "'active'", Valid: true}, user.Column("status").Default)
+	assert.EqualValues(t, sql.NullString{String: "CURRENT_TIMESTAMP", Valid: true}, user.Column("created_at").Default)
+	assert.EqualValues(t, []*dialect.IndexInfo{
+		{Name: "idx_ddl_user_addr", Columns: []string{"addr_city", "addr_street"}},
+		{Name: "idx_ddl_user_status", Columns: []string{"status"}},
+		{Name: "uk_ddl_user_email", Unique: true, Columns: []string{"email"}},
+	}, user.Indexes)
+	assert.EqualValues(t, []*dialect.ForeignKeyInfo{
+		{Columns: []string{"dept_id"}, RefTable: "ddl_dept", RefColumns: []string{"id"}},
+	}, user.ForeignKeys)
+
+	dept, err := m.Table(ctx, "", "ddl_dept")
+	assert.NoError(t, err)
+	assert.EqualValues(t, []*dialect.IndexInfo{
+		{Name: "sqlite_autoindex_ddl_dept_1", Unique: true, Constraint: true, Columns: []string{"name"}},
+	}, dept.Indexes)
+
+	missing, err := m.Table(ctx, "", "ddl_missing")
+	assert.NoError(t, err)
+	assert.Nil(t, missing)
+
+	// tables created by DDL are the same as models
+	stmts, err := sl.MigratePlan(ctx, models)
+	assert.NoError(t, err)
+	assert.Empty(t, stmts)
+
+	assert.NoError(t, sl.Migrate(ctx, []interface{}{&ddlDeptV2{}}))
+	dept, err = m.Table(ctx, "", "ddl_dept")
+	assert.NoError(t, err)
+	assert.EqualValues(t, &dialect.ColumnInfo{Name: "code", Type: "VARCHAR(8)", NotNull: true, Default: sql.NullString{String: "'x'", Valid: true}}, dept.Column("code"))
+	assert.EqualValues(t, 2, len(dept.Indexes))
+
+	stmts, err = sl.MigratePlan(ctx, []interface{}{&ddlDeptV2{}})
+	assert.NoError(t, err)
+	assert.Empty(t, stmts)
+}
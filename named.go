@@ -0,0 +1,147 @@
+package layer
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/meilihao/layer/schema"
+)
+
+var (
+	ErrNamedArgNotFound = errors.New("layer : named arg not found")
+	ErrNamedEmptySlice  = errors.New("layer : named arg is an empty slice")
+)
+
+// Named rewrite query with `:name` or `@name` placeholders into the positional form of the dialect.
+// arg is a map[string]interface{} or a model struct, whose fields are found by db name or raw name.
+// slice values are expanded, so `id IN (:ids)` becomes `id IN (?,?,?)`. `::` casts, `@@` variables,
+// string literals, comments and dollar-quoted bodies are kept as is.
+func (l *Layer) Named(query string, arg interface{}) (string, []interface{}, error) {
+	get, err := l.namedGetter(arg)
+	if err != nil {
+		return "", nil, err
+	}
+
+	var b strings.Builder
+	b.Grow(len(query))
+	args := make([]interface{}, 0, 8)
+	mysql := isMySQL(l.dialecter)
+
+	for i := 0; i < len(query); {
+		if j := sqlSkip(query, i, mysql); j > i {
+			b.WriteString(query[i:j])
+			i = j
+			continue
+		}
+
+		c := query[i]
+		if (c != ':' && c != '@') || i+1 >= len(query) || !isIdentByte(query[i+1], false) ||
+			(i > 0 && (query[i-1] == c || isIdentByte(query[i-1], true))) {
+			b.WriteByte(c)
+			i++
+			continue
+		}
+
+		j := i + 1
+		for j < len(query) && isIdentByte(query[j], true) {
+			j++
+		}
+		name := query[i+1 : j]
+		i = j
+
+		v, ok := get(name)
+		if !ok {
+			return "", nil, fmt.Errorf("%w: %s", ErrNamedArgNotFound, name)
+		}
+
+		values, isList := condList(v)
+		if !isList {
+			values = []interface{}{v}
+		} else if len(values) == 0 {
+			return "", nil, fmt.Errorf("%w: %s", ErrNamedEmptySlice, name)
+		}
+
+		for idx, v := range values {
+			if idx > 0 {
+				b.WriteByte(',')
+			}
+			b.WriteString(l.dialecter.Arg(len(args)))
+			args = append(args, v)
+		}
+	}
+
+	return b.String(), args, nil
+}
+
+// namedGetter get value by name from map or struct
+func (l *Layer) namedGetter(arg interface{}) (func(string) (interface{}, bool), error) {
+	if m, ok := arg.(map[string]interface{}); ok {
+		return func(name string) (interface{}, bool) {
+			v, ok := m[name]
+			return v, ok
+		}, nil
+	}
+
+	rv := reflect.ValueOf(arg)
+	if rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return nil, ErrUsingNilPtrModelData
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil, ErrUsingNotStructModel
+	}
+	// not addressable, nil embedded pointers are kept
+	rv = reflect.ValueOf(rv.Interface())
+
+	sc, err := schema.Parse(arg, l.opts.nameMapper)
+	if err != nil {
+		return nil, err
+	}
+
+	byDBName := make(map[string]*schema.Column, len(sc.Columns))
+	for _, c := range sc.Columns {
+		byDBName[c.DBName] = c
+	}
+
+	return func(name string) (interface{}, bool) {
+		c, ok := byDBName[name]
+		if !ok {
+			if c, ok = sc.ColumnsByRawName[name]; !ok {
+				return nil, false
+			}
+		}
+
+		if _, ok = c.FieldValue(rv); !ok {
+			return nil, true
+		}
+		v, err := c.Get(rv)
+
+		return v, err == nil
+	}, nil
+}
+
+// NamedExec exec query with named args, see Named()
+func (l *Layer) NamedExec(query string, arg interface{}) (sql.Result, error) {
+	query, args, err := l.Named(query, arg)
+	if err != nil {
+		return nil, err
+	}
+
+	return l.execContext(context.Background(), &QueryEvent{Op: OpExec, SQL: query, Args: args})
+}
+
+// NamedQuery query with named args, see Named()
+func (l *Layer) NamedQuery(query string, arg interface{}) *Rows {
+	query, args, err := l.Named(query, arg)
+	if err != nil {
+		return &Rows{err: err, l: l}
+	}
+
+	return l.queryContext(context.Background(), &QueryEvent{Op: OpQuery, SQL: query, Args: args})
+}
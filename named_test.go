@@ -0,0 +1,59 @@
+package layer
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type namedUser struct {
+	Id       int `layer:";pk"`
+	UserName string
+	Tags     []string `layer:";json"`
+}
+
+func TestNamed(t *testing.T) {
+	query := "SELECT * FROM t WHERE id IN (:ids) AND name = @name AND ':x' <> \"@y\" AND created::date = :day -- :z\n/* @w */ AND body = $tag$ :v $tag$ AND a = :name"
+	arg := map[string]interface{}{"ids": []int{1, 2, 3}, "name": "a", "day": "2020-01-01"}
+
+	s, args, err := l.Named(query, arg)
+	assert.NoError(t, err)
+	assert.EqualValues(t, "SELECT * FROM t WHERE id IN (?,?,?) AND name = ? AND ':x' <> \"@y\" AND created::date = ? -- :z\n/* @w */ AND body = $tag$ :v $tag$ AND a = ?", s)
+	assert.EqualValues(t, []interface{}{1, 2, 3, "a", "2020-01-01", "a"}, args)
+
+	s, args, err = pg.Named(query, arg)
+	assert.NoError(t, err)
+	assert.EqualValues(t, "SELECT * FROM t WHERE id IN ($1,$2,$3) AND name = $4 AND ':x' <> \"@y\" AND created::date = $5 -- :z\n/* @w */ AND body = $tag$ :v $tag$ AND a = $6", s)
+	assert.EqualValues(t, []interface{}{1, 2, 3, "a", "2020-01-01", "a"}, args)
+
+	// struct fields by db name or raw name, json columns are encoded
+	s, args, err = pg.Named("UPDATE named_user SET user_name = :user_name, tags = :Tags WHERE id = :id AND 'it''s :no' = 'x' AND @@v = 1", &namedUser{Id: 1, UserName: "a", Tags: []string{"x"}})
+	assert.NoError(t, err)
+	assert.EqualValues(t, "UPDATE named_user SET user_name = $1, tags = $2 WHERE id = $3 AND 'it''s :no' = 'x' AND @@v = 1", s)
+	assert.EqualValues(t, []interface{}{"a", []byte(`["x"]`), 1}, args)
+
+	// backslash escapes and `#` comments are mysql only
+	s, args, err = pg.Named("SELECT * FROM t WHERE path = 'C:\\' AND id = :id # :x", map[string]interface{}{"id": 1, "x": 2})
+	assert.NoError(t, err)
+	assert.EqualValues(t, "SELECT * FROM t WHERE path = 'C:\\' AND id = $1 # $2", s)
+	assert.EqualValues(t, []interface{}{1, 2}, args)
+
+	s, args, err = l.Named("SELECT * FROM t WHERE name = 'it\\'s :no' AND id = :id # :x", map[string]interface{}{"id": 1})
+	assert.NoError(t, err)
+	assert.EqualValues(t, "SELECT * FROM t WHERE name = 'it\\'s :no' AND id = ? # :x", s)
+	assert.EqualValues(t, []interface{}{1}, args)
+
+	_, _, err = l.Named("SELECT :a", arg)
+	assert.True(t, errors.Is(err, ErrNamedArgNotFound))
+
+	_, _, err = l.Named("SELECT :ids", map[string]interface{}{"ids": []int{}})
+	assert.True(t, errors.Is(err, ErrNamedEmptySlice))
+
+	_, _, err = l.Named("SELECT :a", 1)
+	assert.True(t, errors.Is(err, ErrUsingNotStructModel))
+
+	_, err = l.NamedExec("SELECT :a", arg)
+	assert.True(t, errors.Is(err, ErrNamedArgNotFound))
+	assert.True(t, errors.Is(l.NamedQuery("SELECT :a", arg).Err(), ErrNamedArgNotFound))
+}
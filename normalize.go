@@ -0,0 +1,164 @@
+package layer
+
+import (
+	"hash/fnv"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/meilihao/layer/dialect"
+)
+
+// inList `IN(?, ?, ?)` after normalized
+var inList = regexp.MustCompile(`(?i)\bIN\(\?(?:, \?)*\)`)
+
+// operator bytes, a run of them is one token like `>=` or `#>>`
+const normalOps = "<>=!~+-*/%|&^#@:"
+
+// sqlKeywords keywords written in upper case by Normalize, other unquoted words are kept as is
+var sqlKeywords = map[string]bool{}
+
+func init() {
+	for _, k := range strings.Fields(`SELECT FROM WHERE AND OR NOT IN IS NULL LIKE ILIKE BETWEEN EXISTS ANY SOME
+		INSERT INTO VALUES UPDATE SET DELETE REPLACE RETURNING DEFAULT ON CONFLICT DO NOTHING DUPLICATE KEY
+		JOIN INNER LEFT RIGHT FULL OUTER CROSS NATURAL USING AS DISTINCT ALL UNION INTERSECT EXCEPT
+		ORDER BY GROUP HAVING LIMIT OFFSET ASC DESC NULLS FIRST LAST WITH RECURSIVE
+		CASE WHEN THEN ELSE END TRUE FALSE FOR SHARE NOWAIT SKIP LOCKED OF
+		OVER PARTITION WINDOW ROWS RANGE GROUPS PRECEDING FOLLOWING CURRENT ROW UNBOUNDED`) {
+		sqlKeywords[k] = true
+	}
+}
+
+// Normalize returns the shape of query for aggregation:
+// literals and placeholders become `?`, signed numbers included, `IN (...)` lists are collapsed, comments are removed,
+// keywords are upper case and tokens are separated by one space, but not after `(` and `.` or before `(`, `)`, `,` and `.`.
+// `"x"` is a literal in mysql but an identifier in others.
+func Normalize(d dialect.Dialecter, query string) string {
+	mysql := isMySQL(d)
+
+	var b strings.Builder
+	b.Grow(len(query))
+
+	// operand is true if the last token is a value, so a following `-` or `+` is binary
+	var operand bool
+	write := func(s string, isOperand bool) {
+		if n := b.Len(); n > 0 && !strings.ContainsRune("(.", rune(b.String()[n-1])) && !strings.ContainsRune("().,", rune(s[0])) {
+			b.WriteByte(' ')
+		}
+		b.WriteString(s)
+		operand = isOperand
+	}
+
+	for i := 0; i < len(query); {
+		if j := sqlSkip(query, i, mysql); j > i {
+			switch query[i] {
+			case '`':
+				write(query[i:j], true)
+			case '"':
+				if mysql {
+					write("?", true)
+				} else {
+					write(query[i:j], true)
+				}
+			case '\'', '$':
+				write("?", true)
+			}
+			// comment is skipped
+			i = j
+			continue
+		}
+
+		c := query[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f':
+			i++
+		case isIdentByte(c, false) || c >= 0x80:
+			j := i + 1
+			for j < len(query) && (isIdentByte(query[j], true) || query[j] == '$' || query[j] >= 0x80) {
+				j++
+			}
+			if w := strings.ToUpper(query[i:j]); sqlKeywords[w] {
+				write(w, w == "NULL" || w == "TRUE" || w == "FALSE")
+			} else {
+				write(query[i:j], true)
+			}
+			i = j
+		case isDigit(c) || (c == '.' && i+1 < len(query) && isDigit(query[i+1])):
+			i = skipNumber(query, i)
+			write("?", true)
+		case (c == '-' || c == '+') && !operand && i+1 < len(query) && (isDigit(query[i+1]) || query[i+1] == '.'):
+			// sign of number
+			i = skipNumber(query, i+1)
+			write("?", true)
+		case c == '$' && i+1 < len(query) && isDigit(query[i+1]):
+			i++
+			for i < len(query) && isDigit(query[i]) {
+				i++
+			}
+			write("?", true)
+		case strings.IndexByte(normalOps, c) >= 0:
+			j := i + 1
+			for j < len(query) && strings.IndexByte(normalOps, query[j]) >= 0 {
+				j++
+			}
+			write(query[i:j], false)
+			i = j
+		default:
+			write(query[i:i+1], c == ')' || c == ']' || c == '?')
+			i++
+		}
+	}
+
+	return inList.ReplaceAllStringFunc(b.String(), func(s string) string {
+		return s[:2] + "(...)"
+	})
+}
+
+// Fingerprint stable hash of Normalize(d, query)
+func Fingerprint(d dialect.Dialecter, query string) string {
+	h := fnv.New64a()
+	h.Write([]byte(Normalize(d, query)))
+
+	return strconv.FormatUint(h.Sum64(), 16)
+}
+
+// Fingerprint fingerprint of the sql built by b
+func (b *SQLBuilder) Fingerprint() string {
+	var d dialect.Dialecter
+	if b.l != nil {
+		d = b.l.dialecter
+	}
+
+	return Fingerprint(d, b.String())
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// skipNumber skip number like 1, 1.5, .5, 1e-3 and 0x1f
+func skipNumber(query string, i int) int {
+	if strings.HasPrefix(query[i:], "0x") || strings.HasPrefix(query[i:], "0X") {
+		i += 2
+		for i < len(query) && (isDigit(query[i]) || (query[i]|0x20 >= 'a' && query[i]|0x20 <= 'f')) {
+			i++
+		}
+		return i
+	}
+
+	for i < len(query) && (isDigit(query[i]) || query[i] == '.') {
+		i++
+	}
+	if i < len(query) && query[i]|0x20 == 'e' {
+		j := i + 1
+		if j < len(query) && (query[j] == '+' || query[j] == '-') {
+			j++
+		}
+		if j < len(query) && isDigit(query[j]) {
+			for i = j; i < len(query) && isDigit(query[i]); i++ {
+			}
+		}
+	}
+
+	return i
+}
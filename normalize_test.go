@@ -0,0 +1,41 @@
+package layer
+
+import (
+	"testing"
+
+	"github.com/meilihao/layer/dialect"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalize(t *testing.T) {
+	my := dialect.NewDialecter("mysql", nil)
+	pg := dialect.NewDialecter("postgres", nil)
+
+	results := []struct {
+		D      dialect.Dialecter
+		Query  string
+		Result string
+	}{
+		{my, "SELECT `id`, name FROM `t1`  WHERE id IN (1, 2, 3) AND name = 'a''b' -- x\n AND score > 1.5e3 # y\n AND c = \"s\" /* z */ AND d = 0x1F", "SELECT `id`, name FROM `t1` WHERE id IN(...) AND name = ? AND score > ? AND c = ? AND d = ?"},
+		{my, "SELECT `id` FROM `t1` WHERE `id` IN (?,?) AND t2.a=?", "SELECT `id` FROM `t1` WHERE `id` IN(...) AND t2.a = ?"},
+		{my, "select * from t where id not in (?)", "SELECT * FROM t WHERE id NOT IN(...)"},
+		{pg, `SELECT "id" FROM "t1" WHERE "id" IN ($1,$2,$3) AND "a" = $4 AND b = $x$ body $x$ AND c #> '{a}' = 'x'`, `SELECT "id" FROM "t1" WHERE "id" IN(...) AND "a" = ? AND b = ? AND c #> ? = ?`},
+		{pg, "INSERT INTO t (a, b) VALUES ($1, -2)", "INSERT INTO t(a, b) VALUES(?, ?)"},
+		{pg, "UPDATE t SET a = a -1, b = (-1.5) WHERE c = -.5 OR d = ?-2", "UPDATE t SET a = a - ?, b =(?) WHERE c = ? OR d = ? - ?"},
+	}
+
+	for _, v := range results {
+		assert.EqualValues(t, v.Result, Normalize(v.D, v.Query))
+	}
+
+	// same shape, same fingerprint
+	assert.EqualValues(t, Fingerprint(my, "SELECT * FROM t WHERE id IN (?,?,?) AND a = 'x'"), Fingerprint(my, "SELECT * FROM t\n\tWHERE id IN (?) AND a = 'yy'"))
+	assert.EqualValues(t, Fingerprint(my, "select * from t where a = -2"), Fingerprint(my, "SELECT * FROM t WHERE a = 2"))
+	assert.NotEqual(t, Fingerprint(my, "SELECT a FROM t"), Fingerprint(my, "SELECT b FROM t"))
+
+	b := NewSQLBuilder(l, nil, 0)
+	assert.NoError(t, Eq{"id": []int{1, 2, 3}}.Build(b))
+	b2 := NewSQLBuilder(l, nil, 0)
+	assert.NoError(t, Eq{"id": []int{1}}.Build(b2))
+	assert.EqualValues(t, b.Fingerprint(), b2.Fingerprint())
+}
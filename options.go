@@ -22,9 +22,15 @@ type options struct {
 	runTest   bool
 	debug     bool
 	skipPing  bool
+	rebind    bool
 
 	// table/column name
 	nameMapper schema.NameMapper
+
+	logger        Logger
+	slowThreshold time.Duration
+	logExplain    bool
+	instrumenter  Instrumenter
 }
 
 // optionFunc is a function to config options
@@ -76,6 +82,34 @@ func WithShowSQL(isShow bool) optionFunc {
 	}
 }
 
+// WithLogger set Logger, default is ZerologLogger
+func WithLogger(logger Logger) optionFunc {
+	return func(o *options) {
+		o.logger = logger
+	}
+}
+
+// WithSlowThreshold queries slower than threshold are logged at warning level, 0 is disable
+func WithSlowThreshold(threshold time.Duration) optionFunc {
+	return func(o *options) {
+		o.slowThreshold = threshold
+	}
+}
+
+// WithLogExplain log sql with args interpolated by Dialecter.Explain
+func WithLogExplain(explain bool) optionFunc {
+	return func(o *options) {
+		o.logExplain = explain
+	}
+}
+
+// WithInstrumenter set Instrumenter for tracing and metrics
+func WithInstrumenter(instrumenter Instrumenter) optionFunc {
+	return func(o *options) {
+		o.instrumenter = instrumenter
+	}
+}
+
 // WithTableNameMpaper set table/column name's name mapper
 func WithNameMpaper(mapper schema.NameMapper) optionFunc {
 	return func(o *options) {
@@ -98,6 +132,13 @@ func WithDebug(debug bool) optionFunc {
 	}
 }
 
+// WithRebind convert `?` or `$n` placeholders of Exec, Query and AQuery for the dialect, see Rebind()
+func WithRebind(rebind bool) optionFunc {
+	return func(o *options) {
+		o.rebind = rebind
+	}
+}
+
 // WithSkipPing check db connection
 func WithSkipPing(skipPing bool) optionFunc {
 	return func(o *options) {
@@ -0,0 +1,91 @@
+package layer
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/meilihao/layer/dialect"
+)
+
+var (
+	ErrMixedPlaceholder = errors.New("layer : mixed ? and $n placeholders")
+	ErrPlaceholderArgs  = errors.New("layer : placeholders do not match args")
+)
+
+// Rebind convert `?` or `$n` placeholders of query into the positional form of d, by d.Arg().
+// `$n` may be reused or out of order, args are reordered for the `?` form.
+// string literals, quoted identifiers, comments and dollar-quoted bodies are kept as is.
+// note: the `?` json operators of postgres are taken as placeholders.
+func Rebind(d dialect.Dialecter, query string, args []interface{}) (string, []interface{}, error) {
+	var b strings.Builder
+	b.Grow(len(query) + 8)
+
+	var (
+		nq, nd int // numbers of `?` and `$n`
+		out    = make([]interface{}, 0, len(args))
+		mysql  = isMySQL(d)
+	)
+
+	for i := 0; i < len(query); {
+		if j := sqlSkip(query, i, mysql); j > i {
+			b.WriteString(query[i:j])
+			i = j
+			continue
+		}
+
+		switch c := query[i]; {
+		case c == '?':
+			if nq >= len(args) {
+				return "", nil, fmt.Errorf("%w: %d args", ErrPlaceholderArgs, len(args))
+			}
+			nq++
+			b.WriteString(d.Arg(len(out)))
+			out = append(out, args[nq-1])
+			i++
+		case c == '$' && i+1 < len(query) && query[i+1] >= '0' && query[i+1] <= '9' && (i == 0 || !isIdentByte(query[i-1], true)):
+			j := i + 1
+			for j < len(query) && query[j] >= '0' && query[j] <= '9' {
+				j++
+			}
+			n, _ := strconv.Atoi(query[i+1 : j])
+			if n < 1 || n > len(args) {
+				return "", nil, fmt.Errorf("%w: $%d with %d args", ErrPlaceholderArgs, n, len(args))
+			}
+			nd++
+			b.WriteString(d.Arg(len(out)))
+			out = append(out, args[n-1])
+			i = j
+		default:
+			b.WriteByte(c)
+			i++
+		}
+	}
+
+	if nq > 0 && nd > 0 {
+		return "", nil, ErrMixedPlaceholder
+	}
+	if nq > 0 && nq != len(args) {
+		return "", nil, fmt.Errorf("%w: %d placeholders with %d args", ErrPlaceholderArgs, nq, len(args))
+	}
+	if nq == 0 && nd == 0 {
+		return query, args, nil
+	}
+
+	return b.String(), out, nil
+}
+
+// Rebind convert placeholders of query for the dialect of l, see Rebind()
+func (l *Layer) Rebind(query string, args ...interface{}) (string, []interface{}, error) {
+	return Rebind(l.dialecter, query, args)
+}
+
+// rebind rebind query for Exec, Query and AQuery if WithRebind is set
+func (l *Layer) rebind(query string, args []interface{}) (string, []interface{}, error) {
+	if !l.opts.rebind {
+		return query, args, nil
+	}
+
+	return Rebind(l.dialecter, query, args)
+}
@@ -0,0 +1,56 @@
+package layer
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/meilihao/layer/dialect"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRebind(t *testing.T) {
+	pg := dialect.NewDialecter("postgres", nil)
+	my := dialect.NewDialecter("mysql", nil)
+
+	query := "SELECT * FROM t WHERE a = ? AND b = '?' AND c = \"?\" -- ?\nAND d = ? /* ? */ AND e = $x$ ? $x$ AND f = ?"
+	s, args, err := Rebind(pg, query, []interface{}{1, 2, 3})
+	assert.NoError(t, err)
+	assert.EqualValues(t, "SELECT * FROM t WHERE a = $1 AND b = '?' AND c = \"?\" -- ?\nAND d = $2 /* ? */ AND e = $x$ ? $x$ AND f = $3", s)
+	assert.EqualValues(t, []interface{}{1, 2, 3}, args)
+
+	// $n is reused or out of order
+	s, args, err = Rebind(my, "SELECT * FROM t WHERE a = $2 AND b = $1 AND c = $2 AND d = 'it''s $1' AND e$1 = 1", []interface{}{1, 2})
+	assert.NoError(t, err)
+	assert.EqualValues(t, "SELECT * FROM t WHERE a = ? AND b = ? AND c = ? AND d = 'it''s $1' AND e$1 = 1", s)
+	assert.EqualValues(t, []interface{}{2, 1, 2}, args)
+
+	s, args, err = Rebind(pg, "SELECT * FROM t WHERE a = $2 AND b = $1", []interface{}{1, 2})
+	assert.NoError(t, err)
+	assert.EqualValues(t, "SELECT * FROM t WHERE a = $1 AND b = $2", s)
+	assert.EqualValues(t, []interface{}{2, 1}, args)
+
+	// backslash escapes in quotes are mysql only
+	s, args, err = Rebind(pg, "SELECT * FROM t WHERE a = 'C:\\' AND b = ?", []interface{}{1})
+	assert.NoError(t, err)
+	assert.EqualValues(t, "SELECT * FROM t WHERE a = 'C:\\' AND b = $1", s)
+	assert.EqualValues(t, []interface{}{1}, args)
+
+	s, _, err = Rebind(my, "SELECT 1", nil)
+	assert.NoError(t, err)
+	assert.EqualValues(t, "SELECT 1", s)
+
+	_, _, err = Rebind(pg, "SELECT ?, $1", []interface{}{1})
+	assert.True(t, errors.Is(err, ErrMixedPlaceholder))
+	_, _, err = Rebind(pg, "SELECT ?, ?", []interface{}{1})
+	assert.True(t, errors.Is(err, ErrPlaceholderArgs))
+	_, _, err = Rebind(pg, "SELECT ?", []interface{}{1, 2})
+	assert.True(t, errors.Is(err, ErrPlaceholderArgs))
+	_, _, err = Rebind(my, "SELECT $3", []interface{}{1})
+	assert.True(t, errors.Is(err, ErrPlaceholderArgs))
+
+	pl, err := New(WithDB("postgres", "xxx"), WithRunTest(true), WithRebind(true))
+	assert.NoError(t, err)
+	assert.True(t, errors.Is(pl.AQuery("SELECT ?, $1", 1).Err(), ErrMixedPlaceholder))
+	_, err = pl.Exec("SELECT ?, $1", 1)
+	assert.True(t, errors.Is(err, ErrMixedPlaceholder))
+}
@@ -33,11 +33,12 @@ func (r *Rows) Columns() ([]string, error) {
 	return r.rows.Columns()
 }
 
+// Err error of iteration, translated by the dialect
 func (r *Rows) Err() error {
 	if r.err != nil {
 		return r.err
 	}
-	return r.rows.Err()
+	return r.l.TranslateError(r.rows.Err())
 }
 
 func (r *Rows) Next() bool {
@@ -106,7 +107,10 @@ func (r *Rows) Scan(i interface{}) error {
 	// } else if v.Elem().Kind() == reflect.Ptr {
 	// 	return errors.New("a pointer to a pointer is not allowed")
 	// }
-	v, _ := utils.PtrValue(i)
+	v, _, err := utils.PtrValue(i)
+	if err != nil {
+		return err
+	}
 
 	columns, err := r.rows.Columns()
 	if err != nil {
@@ -137,8 +141,9 @@ func (r *Rows) One(i interface{}) (ok bool, err error) {
 	}
 
 	defer func() {
-		if err = r.rows.Close(); err != nil {
-			log.Error().Err(err).Send()
+		// keep the error of Scan
+		if cerr := r.rows.Close(); cerr != nil {
+			log.Error().Err(cerr).Send()
 		}
 	}()
 
@@ -146,7 +151,7 @@ func (r *Rows) One(i interface{}) (ok bool, err error) {
 		err = r.Scan(i)
 		ok = err == nil
 	} else {
-		err = r.rows.Err()
+		err = r.Err()
 	}
 
 	return
@@ -315,7 +320,11 @@ func (r *Rows) All(i interface{}) error {
 		}
 	}()
 
-	v, p := utils.PtrValue(i)
+	v, p, err := utils.PtrValue(i)
+	if err != nil {
+		return err
+	}
+
 	columns, err := r.Columns()
 	if err != nil {
 		return err
@@ -338,19 +347,22 @@ func (r *Rows) All(i interface{}) error {
 		} else {
 			kt = schema.TypeString
 		}
+		if v.Type().Key() != kt {
+			return fmt.Errorf("%w: map key %v, primary key %v", schema.ErrUnsupportedType, v.Type().Key(), kt)
+		}
 
 		if v.IsNil() {
 			if p {
-				v.Set(reflect.MakeMap(kt))
+				v.Set(reflect.MakeMapWithSize(v.Type(), 0))
 			} else {
-				panic("nil map")
+				return ErrUsingNilMap
 			}
 		}
 
 		return r.allStruct(columns, s, v)
 	case reflect.Slice:
 		if !p {
-			panic("not pointer")
+			return ErrUsingNonPtrModelData
 		}
 
 		t := v.Type().Elem()
@@ -375,5 +387,5 @@ func (r *Rows) All(i interface{}) error {
 		}
 	}
 
-	panic(schema.ErrUnsupportedType)
+	return fmt.Errorf("%w: %T", schema.ErrUnsupportedType, i)
 }
@@ -0,0 +1,57 @@
+package layer
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/meilihao/layer/schema"
+	"github.com/meilihao/layer/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+type rowsUser struct {
+	Id   int `layer:";pk"`
+	Name string
+}
+
+func TestRows_Error(t *testing.T) {
+	sl := newSQLite(t)
+
+	_, err := sl.Exec("CREATE TABLE rows_user (id INTEGER PRIMARY KEY, name TEXT)")
+	assert.NoError(t, err)
+	_, err = sl.Exec("INSERT INTO rows_user (id, name) VALUES (1, 'a'), (2, 'b')")
+	assert.NoError(t, err)
+
+	query := "SELECT id, name FROM rows_user ORDER BY id"
+
+	var us []rowsUser
+	assert.NoError(t, sl.AQuery(query).All(&us))
+	assert.EqualValues(t, []rowsUser{{1, "a"}, {2, "b"}}, us)
+
+	err = sl.AQuery(query).All(nil)
+	assert.True(t, errors.Is(err, utils.ErrNilValue))
+
+	err = sl.AQuery(query).All((*[]rowsUser)(nil))
+	assert.True(t, errors.Is(err, utils.ErrNilPointer))
+
+	err = sl.AQuery(query).All([]rowsUser{})
+	assert.True(t, errors.Is(err, ErrUsingNonPtrModelData))
+
+	err = sl.AQuery(query).All(map[int]rowsUser(nil))
+	assert.True(t, errors.Is(err, ErrUsingNilMap))
+
+	err = sl.AQuery(query).All(&map[string]rowsUser{})
+	assert.True(t, errors.Is(err, schema.ErrUnsupportedType))
+
+	err = sl.AQuery(query).All(&us[0])
+	assert.True(t, errors.Is(err, schema.ErrUnsupportedType))
+
+	rows := sl.AQuery(query)
+	assert.True(t, rows.Next())
+	assert.True(t, errors.Is(rows.Scan(nil), utils.ErrNilValue))
+	assert.True(t, errors.Is(rows.Scan((*rowsUser)(nil)), utils.ErrNilPointer))
+	assert.NoError(t, rows.Close())
+
+	_, err = sl.AQuery(query).One(nil)
+	assert.True(t, errors.Is(err, utils.ErrNilValue))
+}
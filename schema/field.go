@@ -32,6 +32,7 @@ type Field struct {
 	NotNull               bool
 	Unique                bool
 	Version               bool
+	Filterable            bool
 	HasDefaultValue       bool
 	DefaultValue          string
 	DefaultValueInterface interface{}
@@ -164,6 +165,10 @@ func (schema *Schema) ParseField(fieldStruct reflect.StructField, namer NameMapp
 		field.Unique = true
 	}
 
+	if _, ok := field.TagSettings[TagFilter]; ok {
+		field.Filterable = true
+	}
+
 	if v, ok := field.TagSettings[TagComment]; ok {
 		field.Comment = v
 	}
@@ -44,6 +44,7 @@ type Schema struct {
 	Name             string
 	RawName          string // raw dbnanme without mapping
 	DBName           string
+	TableSchema      string // schema(postgres) or database(mysql) of table, see SchemaTabler
 	ModelType        reflect.Type
 	Columns          []*Column
 	ColumnsByRawName map[string]*Column
@@ -64,11 +65,39 @@ type Tabler interface {
 	TableName() string
 }
 
+// SchemaTabler get schema(postgres) or database(mysql) which the table belongs to
+type SchemaTabler interface {
+	TableSchema() string
+}
+
 var (
 	SchemaCache = NewCache()
 )
 
 func Parse(dest interface{}, namer NameMapper) (*Schema, error) {
+	t, err := ModelType(dest)
+	if err != nil {
+		return nil, err
+	}
+
+	SchemaCache.RLock()
+	s, ok := SchemaCache.Store[t]
+	SchemaCache.RUnlock()
+	if !ok {
+		SchemaCache.Lock()
+		defer SchemaCache.Unlock()
+		s, ok = SchemaCache.Store[t]
+		if ok {
+			return s, nil
+		}
+
+		return parse(t, namer, 1)
+	}
+	return s, nil
+}
+
+// ModelType struct type of dest, which is a struct or a pointer, slice or map of it
+func ModelType(dest interface{}) (reflect.Type, error) {
 	if dest == nil {
 		return nil, fmt.Errorf("%w: %+v", ErrUnsupportedType, dest)
 	}
@@ -100,20 +129,22 @@ func Parse(dest interface{}, namer NameMapper) (*Schema, error) {
 		return nil, fmt.Errorf("%w: %s", ErrUnsupportedType, TypePath(t.PkgPath(), t.Name()))
 	}
 
-	SchemaCache.RLock()
-	s, ok := SchemaCache.Store[t]
-	SchemaCache.RUnlock()
-	if !ok {
-		SchemaCache.Lock()
-		defer SchemaCache.Unlock()
-		s, ok = SchemaCache.Store[t]
-		if ok {
-			return s, nil
-		}
+	return t, nil
+}
 
-		return parse(t, namer, 1)
+// TableName raw name and schema of the table of model type t, which do not depend on NameMapper
+func TableName(t reflect.Type) (rawName, tableSchema string) {
+	rawName = t.Name()
+
+	modelValue := reflect.New(t)
+	if tabler, ok := modelValue.Interface().(Tabler); ok {
+		rawName = tabler.TableName()
 	}
-	return s, nil
+	if tabler, ok := modelValue.Interface().(SchemaTabler); ok {
+		tableSchema = tabler.TableSchema()
+	}
+
+	return
 }
 
 func parse(t reflect.Type, namer NameMapper, level int) (*Schema, error) {
@@ -143,10 +174,7 @@ func (schema *Schema) parse(namer NameMapper, level int) error {
 		return fmt.Errorf("%w: %+v", ErrParseLoop, schema.ModelType)
 	}
 
-	modelValue := reflect.New(schema.ModelType)
-	if tabler, ok := modelValue.Interface().(Tabler); ok {
-		schema.RawName = tabler.TableName()
-	}
+	schema.RawName, schema.TableSchema = TableName(schema.ModelType)
 	schema.DBName = namer.EntityMap(schema.RawName)
 
 	for i := 0; i < schema.ModelType.NumField(); i++ {
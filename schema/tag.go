@@ -29,6 +29,7 @@ const (
 	TagMany2Many = "many2many"
 	TagJSON      = "json"
 	TagXML       = "xml"
+	TagFilter    = "filter" // column can be used by layer.ParseFilter
 )
 
 const (
@@ -138,4 +139,7 @@ var TagCheckers = map[string]*TagChecker{
 		Name:          TagXML,
 		ConflictGroup: []string{ConflictGroupEncoding},
 	},
+	TagFilter: &TagChecker{
+		Name: TagFilter,
+	},
 }
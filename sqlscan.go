@@ -0,0 +1,78 @@
+package layer
+
+import (
+	"strings"
+
+	"github.com/meilihao/layer/dialect"
+)
+
+// sqlSkip returns the end of the string literal, quoted identifier, comment or dollar-quoted body
+// which starts at i, or i if there is none. `#` comments and backslash escapes in quotes are mysql only.
+func sqlSkip(query string, i int, mysql bool) int {
+	switch c := query[i]; c {
+	case '\'', '"', '`':
+		for j := i + 1; j < len(query); j++ {
+			switch query[j] {
+			case '\\':
+				if mysql && c != '`' {
+					j++
+				}
+			case c:
+				// doubled quote is an escaped one
+				if j+1 < len(query) && query[j+1] == c {
+					j++
+					continue
+				}
+				return j + 1
+			}
+		}
+		return len(query)
+	case '-':
+		if i+1 < len(query) && query[i+1] == '-' {
+			if j := strings.IndexByte(query[i:], '\n'); j >= 0 {
+				return i + j + 1
+			}
+			return len(query)
+		}
+	case '#':
+		if !mysql {
+			break
+		}
+		if j := strings.IndexByte(query[i:], '\n'); j >= 0 {
+			return i + j + 1
+		}
+		return len(query)
+	case '/':
+		if i+1 < len(query) && query[i+1] == '*' {
+			if j := strings.Index(query[i+2:], "*/"); j >= 0 {
+				return i + 2 + j + 2
+			}
+			return len(query)
+		}
+	case '$':
+		// $tag$ ... $tag$ of postgres, $1 is a placeholder
+		j := i + 1
+		for j < len(query) && isIdentByte(query[j], j > i+1) {
+			j++
+		}
+		if j < len(query) && query[j] == '$' {
+			tag := query[i : j+1]
+			if k := strings.Index(query[j+1:], tag); k >= 0 {
+				return j + 1 + k + len(tag)
+			}
+			return len(query)
+		}
+	}
+
+	return i
+}
+
+// isIdentByte letter, '_' or digit if digit is true
+func isIdentByte(c byte, digit bool) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (digit && c >= '0' && c <= '9')
+}
+
+// isMySQL whether d is mysql, which has `#` comments and backslash escapes
+func isMySQL(d dialect.Dialecter) bool {
+	return d != nil && d.Dialect() == "mysql"
+}
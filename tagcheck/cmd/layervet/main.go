@@ -0,0 +1,13 @@
+// Command layervet checks the `layer` struct tags, see package tagcheck.
+// tagcheck uses the layer of the same repository by a replace directive, so install it from the repository:
+//
+//	cd layer/tagcheck && go install ./cmd/layervet
+//	go vet -vettool=$(which layervet) ./...
+package main
+
+import (
+	"github.com/meilihao/layer/tagcheck"
+	"golang.org/x/tools/go/analysis/unitchecker"
+)
+
+func main() { unitchecker.Main(tagcheck.Analyzer) }
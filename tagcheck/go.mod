@@ -0,0 +1,19 @@
+module github.com/meilihao/layer/tagcheck
+
+go 1.22.0
+
+require (
+	github.com/meilihao/layer v0.0.0
+	github.com/stretchr/testify v1.6.1
+	golang.org/x/tools v0.26.0
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.0 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	golang.org/x/mod v0.21.0 // indirect
+	golang.org/x/sync v0.8.0 // indirect
+	gopkg.in/yaml.v3 v3.0.0-20200313102051-9f266ea9e77c // indirect
+)
+
+replace github.com/meilihao/layer => ../
@@ -0,0 +1,496 @@
+// Package tagcheck defines an analyzer which checks the `layer` struct tags statically,
+// with the same rules of schema.Parse: schema.TagCheckers, conflict groups, autoincr with pk
+// and duplicate columns.
+//
+// used by tagcheck/cmd/layervet, which is a separate module to keep golang.org/x/tools out of layer:
+//
+//	go vet -vettool=$(which layervet) ./...
+package tagcheck
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/meilihao/layer/schema"
+	"golang.org/x/tools/go/analysis"
+)
+
+const Doc = `check layer struct tags
+
+reports unknown tags, tags on mismatched types (for example version on a string),
+conflicting tags in the same conflict group of schema.TagCheckers, autoincr without pk,
+embedded on non-struct and duplicate columns, which are errors of schema.Parse at runtime.`
+
+var Analyzer = &analysis.Analyzer{
+	Name: "layertag",
+	Doc:  Doc,
+	Run:  run,
+}
+
+// typeChecks static version of TagChecker.CheckFn, must cover all TagCheckers with CheckFn
+var typeChecks = map[string]func(types.Type) bool{
+	schema.TagAutoIncr:  isIntegers,
+	schema.TagCreatedAt: isTimes,
+	schema.TagUpdatedAt: isTimes,
+	schema.TagDeletedAt: isTimes,
+	schema.TagVersion:   isIntegers,
+	schema.TagOne2One:   isStruct,
+	schema.TagOne2Many:  isStructs,
+	schema.TagMany2One:  isStruct,
+	schema.TagMany2Many: isStructs,
+}
+
+// flagTags are tags often written without the leading ";", which makes them the column name
+var flagTags = map[string]bool{
+	schema.TagPK:        true,
+	schema.TagAutoIncr:  true,
+	schema.TagNotNull:   true,
+	schema.TagUnique:    true,
+	schema.TagJSON:      true,
+	schema.TagXML:       true,
+	schema.TagEmbedded:  true,
+	schema.TagFilter:    true,
+	schema.TagOne2One:   true,
+	schema.TagOne2Many:  true,
+	schema.TagMany2One:  true,
+	schema.TagMany2Many: true,
+}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	for _, file := range pass.Files {
+		ast.Inspect(file, func(n ast.Node) bool {
+			spec, ok := n.(*ast.TypeSpec)
+			if !ok {
+				return true
+			}
+			st, ok := spec.Type.(*ast.StructType)
+			if !ok || !hasLayerTag(st) {
+				return true
+			}
+
+			obj := pass.TypesInfo.Defs[spec.Name]
+			if obj == nil {
+				return true
+			}
+			if s, ok := obj.Type().Underlying().(*types.Struct); ok {
+				checkStruct(pass, st, s)
+			}
+
+			return true
+		})
+	}
+
+	return nil, nil
+}
+
+func hasLayerTag(st *ast.StructType) bool {
+	for _, f := range st.Fields.List {
+		if f.Tag == nil {
+			continue
+		}
+		if _, ok := reflect.StructTag(unquote(f.Tag.Value)).Lookup(schema.DefaultTagIdentifier); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// column is a column of struct, like schema.Column
+type column struct {
+	raw   string
+	field *types.Var
+	tags  map[string]string
+	isPK  bool
+}
+
+func checkStruct(pass *analysis.Pass, st *ast.StructType, s *types.Struct) {
+	// one ast.Field per types.Var
+	var fields []*ast.Field
+	for _, f := range st.Fields.List {
+		n := len(f.Names)
+		if n == 0 {
+			n = 1
+		}
+		for i := 0; i < n; i++ {
+			fields = append(fields, f)
+		}
+	}
+	if len(fields) != s.NumFields() {
+		return
+	}
+
+	valid := true
+	for i := 0; i < s.NumFields(); i++ {
+		v := s.Field(i)
+		if !v.Exported() || fields[i].Tag == nil {
+			continue
+		}
+
+		if !checkField(pass, fields[i], v) {
+			valid = false
+		}
+	}
+	if !valid {
+		// columns of invalid fields are unreliable
+		return
+	}
+
+	cols := columns(s, "", 0)
+	seen := make(map[string]*column, len(cols))
+	single := map[string]*column{}
+	for _, c := range cols {
+		if p, ok := seen[c.raw]; ok {
+			pass.Reportf(c.field.Pos(), "duplicate column %s, also defined by %s", c.raw, p.field.Name())
+			continue
+		}
+		seen[c.raw] = c
+
+		for _, tag := range []string{schema.TagAutoIncr, schema.TagVersion, schema.TagDeletedAt, schema.TagUpdatedAt} {
+			if _, ok := c.tags[tag]; !ok || (tag == schema.TagAutoIncr && !c.isPK) {
+				continue
+			}
+			if p, ok := single[tag]; ok {
+				pass.Reportf(c.field.Pos(), "duplicate %s column %s, also defined by %s", tag, c.raw, p.raw)
+			} else {
+				single[tag] = c
+			}
+		}
+	}
+}
+
+// checkField checks tags of one field like schema.ParseField, reports false if there is an error
+func checkField(pass *analysis.Pass, f *ast.Field, v *types.Var) bool {
+	value, ok := reflect.StructTag(unquote(f.Tag.Value)).Lookup(schema.DefaultTagIdentifier)
+	if !ok {
+		return true
+	}
+
+	raw := strings.TrimSpace(value)
+	if flagTags[raw] {
+		pass.Report(analysis.Diagnostic{
+			Pos:     f.Tag.Pos(),
+			End:     f.Tag.End(),
+			Message: fmt.Sprintf("%q is used as the column name, tags must be after \";\"", raw),
+			SuggestedFixes: []analysis.SuggestedFix{
+				fixTag(f.Tag, value, ";"+raw, "Add \";\" before "+raw),
+			},
+		})
+
+		return false
+	}
+
+	keys, settings := parseTag(value)
+	if settings[schema.TagName] == "-" {
+		return true
+	}
+
+	typ := v.Type()
+	if p, ok := typ.Underlying().(*types.Pointer); ok {
+		typ = p.Elem()
+	}
+
+	ok = true
+	cg := make(map[string]string, 3)
+	for _, tag := range keys {
+		checker := schema.TagCheckers[tag]
+		if checker == nil {
+			d := analysis.Diagnostic{
+				Pos:     f.Tag.Pos(),
+				End:     f.Tag.End(),
+				Message: fmt.Sprintf("%s: %s", schema.ErrUnsupportedTag, tag),
+			}
+			if s := suggestTag(tag); s != "" {
+				d.SuggestedFixes = []analysis.SuggestedFix{
+					fixTag(f.Tag, value, replaceUnit(value, tag, s), fmt.Sprintf("Replace %s with %s", tag, s)),
+				}
+			}
+			pass.Report(d)
+			ok = false
+
+			continue
+		}
+
+		if fn := typeChecks[tag]; fn != nil && !fn(typ) {
+			pass.Reportf(f.Tag.Pos(), "%s: %s on %s", schema.ErrTypeMismatchTag, tag, v.Type())
+			ok = false
+		}
+
+		for _, g := range checker.ConflictGroup {
+			if prev, has := cg[g]; has {
+				pass.Reportf(f.Tag.Pos(), "%s: (%s:%s) in group %s", schema.ErrTagConflict, prev, tag, g)
+				ok = false
+
+				break
+			}
+			cg[g] = tag
+		}
+	}
+
+	if prev, has := cg[schema.ConflictGroupOthers]; has && len(cg) > 1 {
+		pass.Reportf(f.Tag.Pos(), "%s: %s can not be used with other tags", schema.ErrTagConflictOthers, prev)
+		ok = false
+	}
+
+	if _, has := settings[schema.TagAutoIncr]; has {
+		if _, pk := settings[schema.TagPK]; !pk {
+			pass.Report(analysis.Diagnostic{
+				Pos:     f.Tag.Pos(),
+				End:     f.Tag.End(),
+				Message: fmt.Sprintf("%s: %s", schema.ErrAutoIncrWithPK, v.Name()),
+				SuggestedFixes: []analysis.SuggestedFix{
+					fixTag(f.Tag, value, replaceUnit(value, schema.TagAutoIncr, schema.TagPK+";"+schema.TagAutoIncr), "Add pk"),
+				},
+			})
+			ok = false
+		}
+	}
+
+	if _, has := settings[schema.TagEmbedded]; has || (v.Anonymous() && !isValuer(v.Type())) {
+		if !isStruct(typ) {
+			pass.Reportf(f.Tag.Pos(), "invalid embedded struct for field %s, should be struct, but got %s", v.Name(), v.Type())
+			ok = false
+		}
+	}
+
+	return ok
+}
+
+// columns of s like schema.parse, level avoids the loop of embedded structs
+func columns(s *types.Struct, prefix string, level int) []*column {
+	if level > 8 {
+		return nil
+	}
+
+	var cols []*column
+	for i := 0; i < s.NumFields(); i++ {
+		v := s.Field(i)
+		if !v.Exported() {
+			continue
+		}
+
+		value, _ := reflect.StructTag(s.Tag(i)).Lookup(schema.DefaultTagIdentifier)
+		_, settings := parseTag(value)
+		raw := v.Name()
+		if name := settings[schema.TagName]; name == "-" {
+			continue
+		} else if name != "" {
+			raw = name
+		}
+
+		typ := v.Type()
+		if p, ok := typ.Underlying().(*types.Pointer); ok {
+			typ = p.Elem()
+		}
+
+		if prefixEmbedded, ok := settings[schema.TagEmbedded]; ok || (v.Anonymous() && !isValuer(v.Type())) {
+			if es, ok := typ.Underlying().(*types.Struct); ok {
+				for _, c := range columns(es, prefix+prefixEmbedded, level+1) {
+					// tags of the embedded field cover the ones of its columns
+					for k, tv := range settings {
+						c.tags[k] = tv
+					}
+					c.field = v
+					cols = append(cols, c)
+				}
+			}
+
+			continue
+		}
+
+		if rel := relationshipTag(settings); rel != "" {
+			if rel != schema.TagOne2One && rel != schema.TagMany2One {
+				continue
+			}
+			if rs, ok := typ.Underlying().(*types.Struct); ok {
+				for _, c := range columns(rs, "", level+1) {
+					if c.isPK {
+						cols = append(cols, &column{raw: prefix + raw + c.raw, field: v, tags: map[string]string{}})
+					}
+				}
+			}
+
+			continue
+		}
+
+		_, pk := settings[schema.TagPK]
+		cols = append(cols, &column{raw: prefix + raw, field: v, tags: settings, isPK: pk})
+	}
+
+	return cols
+}
+
+func relationshipTag(settings map[string]string) string {
+	for _, v := range []string{schema.TagOne2One, schema.TagOne2Many, schema.TagMany2One, schema.TagMany2Many} {
+		if _, ok := settings[v]; ok {
+			return v
+		}
+	}
+
+	return ""
+}
+
+// parseTag is schema.ParseTag keeping the order of tags
+func parseTag(raw string) ([]string, map[string]string) {
+	settings := schema.ParseTag(raw)
+
+	raw = strings.TrimSpace(raw)
+	if !strings.Contains(raw, ";") {
+		return nil, settings
+	}
+
+	var keys []string
+	seen := map[string]bool{}
+	for _, unit := range strings.Split(raw, ";")[1:] {
+		k := strings.TrimSpace(strings.Split(unit, "=")[0])
+		if k == "" || seen[k] {
+			continue
+		}
+		seen[k] = true
+		keys = append(keys, k)
+	}
+
+	return keys, settings
+}
+
+// replaceUnit replaces the tag unit named old of layer tag value
+func replaceUnit(value, old, new string) string {
+	units := strings.Split(value, ";")
+	for i := 1; i < len(units); i++ {
+		subs := strings.SplitN(units[i], "=", 2)
+		if strings.TrimSpace(subs[0]) == old {
+			subs[0] = strings.Replace(subs[0], old, new, 1)
+			units[i] = strings.Join(subs, "=")
+
+			break
+		}
+	}
+
+	return strings.Join(units, ";")
+}
+
+// fixTag replaces layer tag value of lit
+func fixTag(lit *ast.BasicLit, old, new, msg string) analysis.SuggestedFix {
+	tag := strings.Replace(unquote(lit.Value), fmt.Sprintf("%s:%q", schema.DefaultTagIdentifier, old),
+		fmt.Sprintf("%s:%q", schema.DefaultTagIdentifier, new), 1)
+
+	text := strconv.Quote(tag)
+	if strings.HasPrefix(lit.Value, "`") && !strings.Contains(tag, "`") {
+		text = "`" + tag + "`"
+	}
+
+	return analysis.SuggestedFix{
+		Message: msg,
+		TextEdits: []analysis.TextEdit{{
+			Pos:     lit.Pos(),
+			End:     lit.End(),
+			NewText: []byte(text),
+		}},
+	}
+}
+
+// suggestTag the known tag closest to tag
+func suggestTag(tag string) string {
+	names := make([]string, 0, len(schema.TagCheckers))
+	for name := range schema.TagCheckers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	best, dist := "", 3
+	for _, name := range names {
+		if d := distance(strings.ToLower(tag), name); d < dist {
+			best, dist = name, d
+		}
+	}
+
+	return best
+}
+
+// distance is the levenshtein distance
+func distance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = minInt(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+
+	return prev[len(b)]
+}
+
+func minInt(vs ...int) int {
+	m := vs[0]
+	for _, v := range vs[1:] {
+		if v < m {
+			m = v
+		}
+	}
+
+	return m
+}
+
+func unquote(s string) string {
+	if v, err := strconv.Unquote(s); err == nil {
+		return v
+	}
+
+	return s
+}
+
+func isIntegers(t types.Type) bool {
+	b, ok := t.Underlying().(*types.Basic)
+	return ok && b.Info()&types.IsInteger != 0 && b.Kind() != types.Uintptr
+}
+
+func isTimes(t types.Type) bool {
+	if b, ok := t.Underlying().(*types.Basic); ok {
+		return b.Kind() == types.Int64 || b.Kind() == types.Int
+	}
+
+	n, ok := t.(*types.Named)
+	return ok && n.Obj().Pkg() != nil && n.Obj().Pkg().Path() == "time" && n.Obj().Name() == "Time"
+}
+
+func isStruct(t types.Type) bool {
+	_, ok := t.Underlying().(*types.Struct)
+	return ok
+}
+
+func isStructs(t types.Type) bool {
+	var elem types.Type
+	switch u := t.Underlying().(type) {
+	case *types.Slice:
+		elem = u.Elem()
+	case *types.Map:
+		elem = u.Elem()
+	default:
+		return false
+	}
+	if p, ok := elem.Underlying().(*types.Pointer); ok {
+		elem = p.Elem()
+	}
+
+	return isStruct(elem)
+}
+
+// isValuer reports whether t has method Value like driver.Valuer
+func isValuer(t types.Type) bool {
+	return types.NewMethodSet(t).Lookup(nil, "Value") != nil
+}
@@ -0,0 +1,31 @@
+package tagcheck
+
+import (
+	"testing"
+
+	"github.com/meilihao/layer/schema"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestAnalyzer(t *testing.T) {
+	analysistest.RunWithSuggestedFixes(t, analysistest.TestData(), Analyzer, "a")
+}
+
+func TestTypeChecks(t *testing.T) {
+	for name, checker := range schema.TagCheckers {
+		if checker.CheckFn != nil {
+			assert.NotNil(t, typeChecks[name], name)
+		}
+	}
+}
+
+func TestSuggestTag(t *testing.T) {
+	assert.Equal(t, "pk", suggestTag("pkk"))
+	assert.Equal(t, "autoincr", suggestTag("autoinc"))
+	assert.Equal(t, "many2one", suggestTag("Many2One"))
+	assert.Equal(t, "", suggestTag("unknown"))
+
+	assert.Equal(t, ";pk;autoincr", replaceUnit(";autoincr", "autoincr", "pk;autoincr"))
+	assert.Equal(t, "name; pk ;size=10", replaceUnit("name; pkk ;size=10", "pkk", "pk"))
+}
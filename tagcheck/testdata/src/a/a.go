@@ -0,0 +1,54 @@
+package a
+
+import "time"
+
+type Dept struct {
+	Id   int `layer:";pk"`
+	Name string
+}
+
+type Point struct {
+	X int
+	Y int
+}
+
+type Good struct {
+	Id        int64 `layer:";pk;autoincr"`
+	Name      string
+	Nick      string `layer:"nick_name;notnull;comment=nick"`
+	Version   uint   `layer:";version"`
+	CreatedAt time.Time
+	UpdatedAt int64    `layer:";updated_at=milli"`
+	Dept      *Dept    `layer:";many2one"`
+	Pos       Point    `layer:";embedded=Pos"`
+	Tags      []string `layer:";json"`
+	Ignore    chan int `layer:"-"`
+}
+
+type Bad struct {
+	Id      int    `layer:";pkk"`                   // want `unsupported tag: pkk`
+	Uid     int    `layer:"pk"`                     // want `"pk" is used as the column name, tags must be after ";"`
+	Seq     int    `layer:";autoincr"`              // want `autoincr need with pk: Seq`
+	Version string `layer:";version"`               // want `type mismatch tag: version on string`
+	At      int64  `layer:";created_at;updated_at"` // want `tag conflict: \(created_at:updated_at\) in group time`
+	Emb     Point  `layer:";embedded;json"`         // want `tag conflict others: embedded can not be used with other tags`
+	Many    Dept   `layer:";one2many"`              // want `type mismatch tag: one2many on a.Dept`
+	Num     int    `layer:";embedded"`              // want `invalid embedded struct for field Num, should be struct, but got int`
+}
+
+type Dup struct {
+	Id     int `layer:";pk"`
+	Name   string
+	Alias  string `layer:"Name"` // want `duplicate column Name, also defined by Name`
+	DeptId int
+	Dept   *Dept `layer:";many2one"` // want `duplicate column DeptId, also defined by DeptId`
+	V1     int   `layer:";version"`
+	V2     int   `layer:";version"` // want `duplicate version column V2, also defined by V1`
+	PosX   int
+	Pos    Point `layer:";embedded=Pos"` // want `duplicate column PosX, also defined by PosX`
+}
+
+type NoTag struct {
+	Name  string
+	Name2 string
+}
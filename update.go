@@ -155,7 +155,9 @@ func (se *UpdateSession) Update(value interface{}) (interface{}, error) {
 	if se.err != nil {
 		return nil, se.err
 	}
-	se.value, _ = utils.PtrValue(value)
+	if se.value, _, se.err = utils.PtrValue(value); se.err != nil {
+		return nil, se.err
+	}
 	if se.table == "" {
 		se.table = se.schema.DBName
 	}
@@ -245,7 +247,7 @@ func (se *UpdateSession) Update(value interface{}) (interface{}, error) {
 	}
 
 	se.builder = NewSQLBuilder(se.l, se.schema, 128)
-	se.clauses[clause.ClauseUpdate] = clause.Update{Table: clause.Table{Name: se.table}}
+	se.clauses[clause.ClauseUpdate] = clause.Update{Table: modelTable(se.schema, se.table)}
 	se.err = se.clauses.Build(se.builder, clause.ClauseUpdate, clause.ClauseSet, clause.ClauseWhere)
 
 	if se.err != nil {
@@ -253,11 +255,9 @@ func (se *UpdateSession) Update(value interface{}) (interface{}, error) {
 	}
 
 	now := time.Now()
-	if se.debug {
-		log.Info().Msgf("[%f] %s", time.Now().Sub(now).Seconds(), se.builder.String())
-	}
-
 	if se.dryRun {
+		se.l.logQuery(se.context, &QueryEvent{Op: OpUpdate, Table: se.table, SQL: se.builder.String()}, se.debug)
+
 		return true, nil
 	}
 
@@ -269,9 +269,9 @@ func (se *UpdateSession) update(now time.Time) (interface{}, error) {
 		se.context = context.Background()
 	}
 
-	stmt, err := se.l.db.PrepareContext(se.context, se.builder.String())
+	stmt, err := se.l.conn().PrepareContext(se.context, se.builder.String())
 	if err != nil {
-		return nil, err
+		return nil, se.l.TranslateError(err)
 	}
 
 	defer func() {
@@ -324,10 +324,9 @@ func (se *UpdateSession) update1(s *sql.Stmt, v reflect.Value, now time.Time) (_
 		v = v.Elem()
 	}
 
-	a := make([]interface{}, 0, len(se.builder.Columns))
 	var curVersion int64 = -1
 	var isOK bool
-	for idx, c := range se.builder.Columns {
+	for _, c := range se.builder.Columns {
 		if c.IsAutoUpdatedAt() {
 			if !c.SetTime(v, now, se.l.opts.tz, c.Field.TimeLevel) {
 				return false, c.ErrSet()
@@ -338,7 +337,11 @@ func (se *UpdateSession) update1(s *sql.Stmt, v reflect.Value, now time.Time) (_
 				return false, c.ErrSet()
 			}
 		}
+	}
 
+	// args of expressions like clause.Incr are set, the others are taken from v
+	a := make([]interface{}, 0, len(se.builder.Args))
+	for idx, c := range se.builder.ArgColumns {
 		if se.builder.Args[idx] != nil {
 			a = append(a, se.builder.Args[idx])
 
@@ -353,19 +356,18 @@ func (se *UpdateSession) update1(s *sql.Stmt, v reflect.Value, now time.Time) (_
 		a = append(a, i)
 	}
 
-	if se.debug {
-		log.Info().Msg(se.l.dialecter.Explain(se.builder.String(), a))
+	ev := &QueryEvent{Op: OpUpdate, Table: se.table, SQL: se.builder.String(), Args: a}
+	ctx := se.l.startQuery(se.context, ev)
+	r, err := s.ExecContext(ctx, a...)
+	if err == nil {
+		ev.RowsAffected, err = r.RowsAffected()
 	}
-
-	r, err := s.Exec(a...)
-	if err != nil {
-		return
-	}
-	n, err := r.RowsAffected()
-	if err != nil {
+	ev.Err = err
+	se.l.endQuery(ctx, ev, se.debug)
+	if err = ev.Err; err != nil {
 		return
 	}
-	if n == 0 {
+	if n := ev.RowsAffected; n == 0 {
 		return false, nil
 	} else if n == 1 {
 		if curVersion > 0 {
@@ -377,7 +379,7 @@ func (se *UpdateSession) update1(s *sql.Stmt, v reflect.Value, now time.Time) (_
 		return true, nil
 	}
 
-	return false, fmt.Errorf("huge: RowsAffected expected 0 or 1 but was %d", n)
+	return false, fmt.Errorf("huge: RowsAffected expected 0 or 1 but was %d", ev.RowsAffected)
 }
 
 // Update T returns bool, []T returns map[int]struct{}, map[]T returns map[]struct{}.
@@ -1,11 +1,17 @@
 package utils
 
 import (
+	"errors"
 	"reflect"
 	"strconv"
 	"time"
 )
 
+var (
+	ErrNilValue   = errors.New("nil value")
+	ErrNilPointer = errors.New("nil pointer")
+)
+
 func IsInts(k reflect.Kind) bool {
 	switch k {
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
@@ -51,19 +57,20 @@ func IsStructs(t reflect.Type) bool {
 	return false
 }
 
-func PtrValue(i interface{}) (v reflect.Value, isPointer bool) {
+// PtrValue returns the value i points to if i is a pointer
+func PtrValue(i interface{}) (v reflect.Value, isPointer bool, err error) {
 	if i == nil {
-		panic("nil value")
+		return v, false, ErrNilValue
 	}
 
 	v = reflect.ValueOf(i)
 	if v.Kind() == reflect.Ptr {
 		if v.IsNil() {
-			panic("nil pointer")
-		} else {
-			v = v.Elem()
-			isPointer = true
+			return v, true, ErrNilPointer
 		}
+
+		v = v.Elem()
+		isPointer = true
 	}
 
 	return
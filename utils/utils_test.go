@@ -0,0 +1,30 @@
+package utils
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPtrValue(t *testing.T) {
+	_, _, err := PtrValue(nil)
+	assert.True(t, errors.Is(err, ErrNilValue))
+
+	_, p, err := PtrValue((*int)(nil))
+	assert.True(t, p)
+	assert.True(t, errors.Is(err, ErrNilPointer))
+
+	i := 1
+	v, p, err := PtrValue(&i)
+	assert.NoError(t, err)
+	assert.True(t, p)
+	assert.EqualValues(t, reflect.Int, v.Kind())
+	assert.True(t, v.CanSet())
+
+	v, p, err = PtrValue(i)
+	assert.NoError(t, err)
+	assert.False(t, p)
+	assert.False(t, v.CanSet())
+}